@@ -0,0 +1,180 @@
+package promise4g
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// AdaptiveConfig configures an AdaptivePool. Zero values are replaced with defaults.
+type AdaptiveConfig struct {
+	// Name identifies the pool in metrics
+	Name string
+	// InitialLimit is the concurrency limit the pool starts with (default 10)
+	InitialLimit int
+	// MinLimit is the lowest the limit can go (default 1)
+	MinLimit int
+	// MaxLimit is the highest the limit can go (default 1000)
+	MaxLimit int
+	// TargetLatency is the execution time above which a task counts as overload.
+	// If zero, only failures make the limit back off.
+	TargetLatency time.Duration
+	// BackoffRatio is the factor the limit is multiplied by on overload (default 0.9)
+	BackoffRatio float64
+	// IsFailure reports whether an error counts as overload.
+	// By default every error except context.Canceled does.
+	IsFailure func(error) bool
+	// OnLimitChange is called after every change of the limit.
+	// It runs while the pool is locked and must not call back into it.
+	OnLimitChange func(name string, from, to int)
+}
+
+// AdaptivePool is a Pool that adjusts its concurrency limit with AIMD (additive increase, multiplicative decrease).
+// It learns from the promises that run on it: every task that settles slower than the target latency or
+// with a failure shrinks the limit by the backoff ratio, and every healthy task grows it by one while
+// the pool is using at least half of it. Tasks over the limit wait in order until a running task returns.
+type AdaptivePool struct {
+	cfg  AdaptiveConfig
+	pool Pool
+
+	mu       sync.Mutex
+	limit    float64
+	inflight int
+	queue    list.List // of func()
+}
+
+// NewAdaptivePool creates an AdaptivePool that runs admitted tasks on pool
+func NewAdaptivePool(pool Pool, cfg AdaptiveConfig) *AdaptivePool {
+	if pool == nil {
+		panic("pool must not be nil")
+	}
+	if cfg.MinLimit <= 0 {
+		cfg.MinLimit = 1
+	}
+	if cfg.MaxLimit <= 0 {
+		cfg.MaxLimit = 1000
+	}
+	if cfg.MaxLimit < cfg.MinLimit {
+		cfg.MaxLimit = cfg.MinLimit
+	}
+	if cfg.InitialLimit <= 0 {
+		cfg.InitialLimit = 10
+	}
+	cfg.InitialLimit = min(max(cfg.InitialLimit, cfg.MinLimit), cfg.MaxLimit)
+	if cfg.BackoffRatio <= 0 || cfg.BackoffRatio >= 1 {
+		cfg.BackoffRatio = 0.9
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = func(err error) bool {
+			return err != nil && !errors.Is(err, context.Canceled)
+		}
+	}
+	ap := &AdaptivePool{
+		cfg:   cfg,
+		pool:  pool,
+		limit: float64(cfg.InitialLimit),
+	}
+	setAdaptivePoolLimit(cfg.Name, cfg.InitialLimit)
+	return ap
+}
+
+// Go runs f once the number of running tasks is below the limit
+func (ap *AdaptivePool) Go(f func()) {
+	ap.mu.Lock()
+	if ap.queue.Len() > 0 || ap.inflight >= ap.currentLimit() {
+		ap.queue.PushBack(f)
+		ap.mu.Unlock()
+		return
+	}
+	ap.inflight++
+	ap.mu.Unlock()
+	ap.start(f)
+}
+
+// Limit returns the current concurrency limit
+func (ap *AdaptivePool) Limit() int {
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+	return ap.currentLimit()
+}
+
+func (ap *AdaptivePool) String() string {
+	return fmt.Sprintf("adaptive %s", ap.cfg.Name)
+}
+
+// start runs f on the underlying pool. f must already be counted in inflight and ap.mu must not be held,
+// as the underlying pool may block until one of its workers is free.
+func (ap *AdaptivePool) start(f func()) {
+	ap.pool.Go(func() {
+		defer ap.done()
+		f()
+	})
+}
+
+func (ap *AdaptivePool) done() {
+	ap.mu.Lock()
+	ap.inflight--
+	ready := ap.drain()
+	ap.mu.Unlock()
+	ap.startFromWorker(ready)
+}
+
+// drain takes the queued tasks that fit in the limit and counts them in inflight. ap.mu must be held.
+func (ap *AdaptivePool) drain() []func() {
+	var ready []func()
+	for ap.inflight < ap.currentLimit() {
+		front := ap.queue.Front()
+		if front == nil {
+			break
+		}
+		ap.queue.Remove(front)
+		ap.inflight++
+		ready = append(ready, front.Value.(func()))
+	}
+	return ready
+}
+
+// startFromWorker starts tasks taken by drain from inside a task of the underlying pool.
+// Submitting them there could wait for a free worker forever when the current one is the last,
+// so they are handed over from their own goroutines.
+func (ap *AdaptivePool) startFromWorker(ready []func()) {
+	for _, f := range ready {
+		go ap.start(f)
+	}
+}
+
+// currentLimit returns the limit as a whole number of tasks. ap.mu must be held.
+func (ap *AdaptivePool) currentLimit() int {
+	return int(ap.limit)
+}
+
+func (ap *AdaptivePool) observeExecution(d time.Duration, err error) {
+	ap.mu.Lock()
+
+	from := ap.currentLimit()
+	overloaded := ap.cfg.IsFailure(err) || (ap.cfg.TargetLatency > 0 && d > ap.cfg.TargetLatency)
+	switch {
+	case overloaded:
+		ap.limit = max(ap.limit*ap.cfg.BackoffRatio, float64(ap.cfg.MinLimit))
+	case (ap.inflight-1)*2 >= from:
+		// Only grow while the limit is actually in use, an idle pool says nothing about capacity.
+		// The task that has just finished is still counted in inflight until it returns.
+		ap.limit = min(ap.limit+1, float64(ap.cfg.MaxLimit))
+	}
+
+	to := ap.currentLimit()
+	if to == from {
+		ap.mu.Unlock()
+		return
+	}
+	setAdaptivePoolLimit(ap.cfg.Name, to)
+	if ap.cfg.OnLimitChange != nil {
+		ap.cfg.OnLimitChange(ap.cfg.Name, from, to)
+	}
+	ready := ap.drain()
+	ap.mu.Unlock()
+	ap.startFromWorker(ready)
+}
@@ -0,0 +1,140 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/panjf2000/ants/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptivePool(t *testing.T) {
+	t.Run("BacksOffOnFailure", func(t *testing.T) {
+		ctx := context.Background()
+		var mu sync.Mutex
+		var changes [][2]int
+		pool := NewAdaptivePool(newDefaultPool(), AdaptiveConfig{
+			Name:         "backoff",
+			InitialLimit: 10,
+			BackoffRatio: 0.5,
+			OnLimitChange: func(name string, from, to int) {
+				mu.Lock()
+				defer mu.Unlock()
+				changes = append(changes, [2]int{from, to})
+			},
+		})
+
+		for range 3 {
+			_, err := NewWithOptions(func(resolve func(int), reject func(error)) {
+				reject(errors.New("overloaded"))
+			}, WithPool(pool)).Await(ctx)
+			require.Error(t, err)
+		}
+		require.Equal(t, 1, pool.Limit())
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, [][2]int{{10, 5}, {5, 2}, {2, 1}}, changes)
+	})
+
+	t.Run("BacksOffOnSlowTasks", func(t *testing.T) {
+		pool := NewAdaptivePool(newDefaultPool(), AdaptiveConfig{
+			Name:          "slow",
+			InitialLimit:  10,
+			TargetLatency: 5 * time.Millisecond,
+		})
+		_, err := NewWithOptions(func(resolve func(int), reject func(error)) {
+			time.Sleep(20 * time.Millisecond)
+			resolve(1)
+		}, WithPool(pool)).Await(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 9, pool.Limit())
+	})
+
+	t.Run("GrowsUnderLoad", func(t *testing.T) {
+		ctx := context.Background()
+		pool := NewAdaptivePool(newDefaultPool(), AdaptiveConfig{
+			Name:         "grow",
+			InitialLimit: 2,
+			MaxLimit:     3,
+		})
+
+		promises := make([]*Promise[int], 10)
+		for i := range promises {
+			promises[i] = NewWithOptions(func(resolve func(int), reject func(error)) {
+				time.Sleep(5 * time.Millisecond)
+				resolve(i)
+			}, WithPool(pool))
+		}
+		_, err := All(ctx, promises...).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 3, pool.Limit())
+	})
+
+	t.Run("IdleDoesNotGrow", func(t *testing.T) {
+		// Tasks run on the submitting goroutine, so each one has returned before the next is submitted
+		pool := NewAdaptivePool(wrapFunc(func(f func()) {
+			f()
+		}), AdaptiveConfig{
+			Name:         "idle",
+			InitialLimit: 2,
+		})
+		for range 5 {
+			_, err := NewWithOptions(func(resolve func(int), reject func(error)) {
+				resolve(1)
+			}, WithPool(pool)).Await(context.Background())
+			require.NoError(t, err)
+		}
+		require.Equal(t, 2, pool.Limit())
+	})
+
+	t.Run("RespectsLimit", func(t *testing.T) {
+		ctx := context.Background()
+		pool := NewAdaptivePool(newDefaultPool(), AdaptiveConfig{
+			Name:         "respects",
+			InitialLimit: 2,
+			MaxLimit:     2,
+		})
+		var tracker peakTracker
+		promises := make([]*Promise[int], 8)
+		for i := range promises {
+			promises[i] = NewWithOptions(func(resolve func(int), reject func(error)) {
+				done := tracker.trackPeak(1)
+				time.Sleep(5 * time.Millisecond)
+				done()
+				resolve(i)
+			}, WithPool(pool))
+		}
+		results, err := All(ctx, promises...).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7}, results)
+		require.LessOrEqual(t, tracker.max(), int64(2))
+	})
+
+	t.Run("SmallAntsPool", func(t *testing.T) {
+		antsPool, err := ants.NewPool(1)
+		require.NoError(t, err)
+		defer antsPool.Release()
+		pool := NewAdaptivePool(FromAntsPool(antsPool), AdaptiveConfig{
+			Name:         "small-ants",
+			InitialLimit: 4,
+			MaxLimit:     4,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		promises := make([]*Promise[int], 8)
+		for i := range promises {
+			promises[i] = NewWithOptions(func(resolve func(int), reject func(error)) {
+				time.Sleep(time.Millisecond)
+				resolve(i)
+			}, WithPool(pool))
+		}
+		results, err := All(ctx, promises...).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7}, results)
+	})
+}
@@ -0,0 +1,183 @@
+package promise4g
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// admissionQueue admits tasks in order while their total weight stays within its capacity.
+// It is the waiting room shared by WeightedPool and Bulkhead.
+type admissionQueue struct {
+	capacity int64
+	// maxWaiting is the number of tasks that may wait, or negative for no limit
+	maxWaiting int
+	// full is the error tasks are refused with when maxWaiting tasks are already waiting
+	full error
+	// observe is called with the admitted weight and the number of waiting tasks after every change.
+	// It runs while the queue is locked.
+	observe func(used int64, waiting int)
+	// observeWait is called with the time a task waited, whether it was admitted or gave up
+	observeWait func(d time.Duration)
+
+	mu      sync.Mutex
+	used    int64
+	waiters list.List // of *admissionWaiter
+}
+
+type admissionWaiter struct {
+	weight int64
+	ready  chan struct{}
+}
+
+// acquire calls admitted once weight fits in the queue, or fail if ctx is done first or the queue is full
+func (q *admissionQueue) acquire(ctx context.Context, weight int64, admitted func(), fail func(error)) {
+	q.mu.Lock()
+	if q.waiters.Len() == 0 && q.used+weight <= q.capacity {
+		q.used += weight
+		q.changed()
+		q.mu.Unlock()
+		admitted()
+		return
+	}
+	if q.maxWaiting >= 0 && q.waiters.Len() >= q.maxWaiting {
+		q.mu.Unlock()
+		fail(q.full)
+		return
+	}
+	if err := ctx.Err(); err != nil {
+		q.mu.Unlock()
+		fail(err)
+		return
+	}
+	w := &admissionWaiter{weight: weight, ready: make(chan struct{})}
+	elem := q.waiters.PushBack(w)
+	q.changed()
+	q.mu.Unlock()
+
+	clock := ClockFromContext(ctx)
+	waitStart := clock.Now()
+	waited := func() {
+		if q.observeWait != nil {
+			q.observeWait(clock.Now().Sub(waitStart))
+		}
+	}
+	go func() {
+		select {
+		case <-w.ready:
+			waited()
+			admitted()
+		case <-ctx.Done():
+			q.mu.Lock()
+			select {
+			case <-w.ready:
+				// Admitted at the same time the context was done, run it anyway
+				q.mu.Unlock()
+				waited()
+				admitted()
+				return
+			default:
+			}
+			q.waiters.Remove(elem)
+			// The head of the queue may have been blocking lighter tasks behind it
+			q.notify()
+			q.changed()
+			q.mu.Unlock()
+			waited()
+			fail(ctx.Err())
+		}
+	}()
+}
+
+// release gives back the weight of a task that has finished
+func (q *admissionQueue) release(weight int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.used -= weight
+	q.notify()
+	q.changed()
+}
+
+// notify admits waiting tasks in order while they fit. q.mu must be held.
+func (q *admissionQueue) notify() {
+	for {
+		front := q.waiters.Front()
+		if front == nil {
+			return
+		}
+		w := front.Value.(*admissionWaiter)
+		if q.used+w.weight > q.capacity {
+			return
+		}
+		q.used += w.weight
+		q.waiters.Remove(front)
+		close(w.ready)
+	}
+}
+
+// changed reports the state of the queue. q.mu must be held.
+func (q *admissionQueue) changed() {
+	if q.observe != nil {
+		q.observe(q.used, q.waiters.Len())
+	}
+}
+
+// start runs run on pool for a task that has been admitted with weight, and gives the weight back once run returns.
+// If pool refuses the task by panicking, the weight is given back and fail is called with the panic instead.
+func (q *admissionQueue) start(pool Pool, weight int64, run func(), fail func(error)) {
+	startOn(pool, func() {
+		defer q.release(weight)
+		run()
+	}, func(err error) {
+		q.release(weight)
+		fail(err)
+	})
+}
+
+// startOn runs run on pool, or calls fail with the panic if pool refuses it by panicking
+func startOn(pool Pool, run func(), fail func(error)) {
+	var started atomic.Bool
+	defer func() {
+		if r := recover(); r != nil {
+			if started.Load() {
+				// The pool ran the task on this goroutine and the task itself panicked
+				panic(r)
+			}
+			fail(panicToError(r))
+		}
+	}()
+	pool.Go(func() {
+		started.Store(true)
+		run()
+	})
+}
+
+// goAdmitted submits f through admit the way Pool.Go does. A task refused right away panics on the
+// calling goroutine, like a pool that is full or closed. A task refused after it has waited has
+// nobody to report to, so it is dropped and logged with the default logger.
+func goAdmitted(pool Pool, admit func(ctx context.Context, weight int64, run func(), fail func(error)), f func()) {
+	const (
+		pending = iota
+		refused
+		returned
+	)
+	var (
+		state   atomic.Int32
+		refusal error
+	)
+	admit(context.Background(), 1, f, func(err error) {
+		refusal = err
+		if state.CompareAndSwap(pending, refused) {
+			return
+		}
+		if l := getLogger(); l != nil {
+			l.Error("pool refused task", slog.String("pool", poolName(pool)), slog.Any("error", err))
+		}
+	})
+	if !state.CompareAndSwap(pending, returned) {
+		panic(refusal)
+	}
+}
@@ -0,0 +1,225 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AllOption configures AllWithOptions
+type AllOption func(*allOptions)
+
+type allOptions struct {
+	pool          Pool
+	collectErrors bool
+	minSuccess    int
+}
+
+// WithAllPool runs AllWithOptions on the given pool
+func WithAllPool(pool Pool) AllOption {
+	return func(o *allOptions) {
+		o.pool = pool
+	}
+}
+
+// CollectErrors waits for every promise and rejects with a *MultiError holding all rejections,
+// instead of rejecting with the first one
+func CollectErrors() AllOption {
+	return func(o *allOptions) {
+		o.collectErrors = true
+	}
+}
+
+// MinSuccess resolves as soon as k promises have resolved, and rejects with a *MultiError
+// as soon as fewer than k can still resolve. Results of promises that have not resolved are left as zero values.
+func MinSuccess(k int) AllOption {
+	return func(o *allOptions) {
+		o.minSuccess = k
+	}
+}
+
+// AllWithOptions waits for the promises to be resolved according to the given options
+func AllWithOptions[T any](ctx context.Context, promises []*Promise[T], opts ...AllOption) *Promise[[]T] {
+	if len(promises) == 0 {
+		panic("missing promises")
+	}
+
+	o := allOptions{pool: defaultPool}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.pool == nil {
+		panic("pool must not be nil")
+	}
+	return all(ctx, promises, o)
+}
+
+// settledResult is the outcome of the promise at index in the input of a combinator
+type settledResult[T any] struct {
+	index int
+	value T
+	err   error
+}
+
+func all[T any](ctx context.Context, promises []*Promise[T], o allOptions) *Promise[[]T] {
+	return NewWithPool(func(resolve func([]T), reject func(error)) {
+		if o.minSuccess > len(promises) {
+			reject(fmt.Errorf("cannot get %d successes out of %d promises", o.minSuccess, len(promises)))
+			return
+		}
+
+		results := make([]T, len(promises))
+		errs := make(map[int]error)
+		successes := 0
+		settled := awaitEach(ctx, o.pool, promises, func(s settledResult[T]) bool {
+			if s.err != nil {
+				if !o.collectErrors && o.minSuccess == 0 {
+					reject(s.err)
+					return true
+				}
+				errs[s.index] = s.err
+				if o.minSuccess > 0 && len(promises)-len(errs) < o.minSuccess {
+					reject(&MultiError{Errors: errs})
+					return true
+				}
+				return false
+			}
+
+			results[s.index] = s.value
+			successes++
+			if o.minSuccess > 0 && successes == o.minSuccess {
+				resolve(results)
+				return true
+			}
+			return false
+		})
+		if settled {
+			return
+		}
+
+		if len(errs) > 0 {
+			reject(&MultiError{Errors: errs})
+			return
+		}
+		resolve(results)
+	}, o.pool)
+}
+
+// awaitEach awaits the promises on pool and passes their outcomes to handle in the order they settle,
+// until handle reports that the outcome is known. It then stops waiting for the remaining promises.
+// It reports whether handle stopped it before every promise had settled.
+func awaitEach[T any](ctx context.Context, pool Pool, promises []*Promise[T], handle func(s settledResult[T]) bool) bool {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ch := make(chan settledResult[T], len(promises))
+	for i, p := range promises {
+		// Their outcome is passed on to handle, even if it stops waiting for them
+		p.markHandled()
+		pool.Go(func() {
+			value, err := p.Await(ctx)
+			ch <- settledResult[T]{index: i, value: value, err: err}
+		})
+	}
+
+	for range promises {
+		if handle(<-ch) {
+			return true
+		}
+	}
+	return false
+}
+
+// Some resolves with the values of the first k promises to resolve, in the order they resolved.
+// It rejects with a *MultiError as soon as fewer than k promises can still resolve.
+// Once the outcome is known it stops waiting for the remaining promises, but cannot stop their tasks;
+// use SomeFunc to have them canceled.
+func Some[T any](ctx context.Context, k int, promises ...*Promise[T]) *Promise[[]T] {
+	return some(ctx, k, promises, func() {})
+}
+
+// SomeFunc calls every factory with a context that is canceled once the outcome is known,
+// so that the attempts left over stop as well. It then behaves like Some.
+func SomeFunc[T any](ctx context.Context, k int, factories ...func(ctx context.Context) *Promise[T]) *Promise[[]T] {
+	ctx, cancel := context.WithCancel(ctx)
+	promises := make([]*Promise[T], len(factories))
+	for i, factory := range factories {
+		promises[i] = factory(ctx)
+	}
+	return some(ctx, k, promises, cancel)
+}
+
+// some implements Some, calling done once the returned Promise has settled
+func some[T any](ctx context.Context, k int, promises []*Promise[T], done func()) *Promise[[]T] {
+	return NewWithPool(func(resolve func([]T), reject func(error)) {
+		defer done()
+		if k <= 0 {
+			resolve([]T{})
+			return
+		}
+		if k > len(promises) {
+			reject(fmt.Errorf("cannot get %d successes out of %d promises", k, len(promises)))
+			return
+		}
+
+		values := make([]T, 0, k)
+		errs := make(map[int]error)
+		awaitEach(ctx, defaultPool, promises, func(s settledResult[T]) bool {
+			if s.err != nil {
+				errs[s.index] = s.err
+				if len(promises)-len(errs) < k {
+					reject(&MultiError{Errors: errs})
+					return true
+				}
+				return false
+			}
+
+			values = append(values, s.value)
+			if len(values) == k {
+				resolve(values)
+				return true
+			}
+			return false
+		})
+	}, defaultPool)
+}
+
+// MultiError holds the rejections of several promises, keyed by their index in the input
+type MultiError struct {
+	Errors map[int]error
+}
+
+func (e *MultiError) Error() string {
+	indexes := e.indexes()
+	msgs := make([]string, len(indexes))
+	for i, index := range indexes {
+		msgs[i] = fmt.Sprintf("promise %d: %v", index, e.Errors[index])
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors ordered by index, so errors.Is and errors.As look through all of them
+func (e *MultiError) Unwrap() []error {
+	indexes := e.indexes()
+	errs := make([]error, len(indexes))
+	for i, index := range indexes {
+		errs[i] = e.Errors[index]
+	}
+	return errs
+}
+
+// Join returns the errors ordered by index combined with errors.Join
+func (e *MultiError) Join() error {
+	return errors.Join(e.Unwrap()...)
+}
+
+func (e *MultiError) indexes() []int {
+	indexes := make([]int, 0, len(e.Errors))
+	for index := range e.Errors {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
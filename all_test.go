@@ -0,0 +1,156 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllWithOptions(t *testing.T) {
+	errA := errors.New("a")
+	errB := errors.New("b")
+
+	t.Run("CollectErrors", func(t *testing.T) {
+		ctx := context.Background()
+		p1 := New(func(resolve func(int), reject func(error)) {
+			reject(errA)
+		})
+		p2 := New(func(resolve func(int), reject func(error)) {
+			resolve(2)
+		})
+		p3 := New(func(resolve func(int), reject func(error)) {
+			time.Sleep(20 * time.Millisecond)
+			reject(errB)
+		})
+
+		_, err := AllWithOptions(ctx, []*Promise[int]{p1, p2, p3}, CollectErrors()).Await(ctx)
+		var multiErr *MultiError
+		require.ErrorAs(t, err, &multiErr)
+		require.Equal(t, map[int]error{0: errA, 2: errB}, multiErr.Errors)
+		require.ErrorIs(t, err, errA)
+		require.ErrorIs(t, err, errB)
+		require.Equal(t, "promise 0: a; promise 2: b", err.Error())
+		require.Equal(t, "a\nb", multiErr.Join().Error())
+	})
+
+	t.Run("MinSuccess", func(t *testing.T) {
+		ctx := context.Background()
+		p1 := New(func(resolve func(int), reject func(error)) {
+			resolve(1)
+		})
+		p2 := New(func(resolve func(int), reject func(error)) {
+			reject(errA)
+		})
+		p3 := New(func(resolve func(int), reject func(error)) {
+			time.Sleep(20 * time.Millisecond)
+			resolve(3)
+		})
+		// p4 does not settle before the test is over, so the quorum must not wait for it
+		release := make(chan struct{})
+		defer close(release)
+		p4 := New(func(resolve func(int), reject func(error)) {
+			<-release
+			resolve(4)
+		})
+
+		results, err := AllWithOptions(ctx, []*Promise[int]{p1, p2, p3, p4}, MinSuccess(2)).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 0, 3, 0}, results)
+	})
+
+	t.Run("MinSuccessImpossible", func(t *testing.T) {
+		ctx := context.Background()
+		p1 := New(func(resolve func(int), reject func(error)) {
+			reject(errA)
+		})
+		p2 := New(func(resolve func(int), reject func(error)) {
+			reject(errB)
+		})
+		release := make(chan struct{})
+		defer close(release)
+		p3 := New(func(resolve func(int), reject func(error)) {
+			<-release
+			resolve(3)
+		})
+
+		_, err := AllWithOptions(ctx, []*Promise[int]{p1, p2, p3}, MinSuccess(2)).Await(ctx)
+		var multiErr *MultiError
+		require.ErrorAs(t, err, &multiErr)
+		require.Len(t, multiErr.Errors, 2)
+	})
+}
+
+func TestSome(t *testing.T) {
+	t.Run("FirstK", func(t *testing.T) {
+		ctx := context.Background()
+		p1 := New(func(resolve func(string), reject func(error)) {
+			time.Sleep(40 * time.Millisecond)
+			resolve("slow")
+		})
+		p2 := New(func(resolve func(string), reject func(error)) {
+			resolve("fast")
+		})
+		p3 := New(func(resolve func(string), reject func(error)) {
+			reject(errors.New("error"))
+		})
+		// p4 does not settle before the test is over, so Some must not wait for it
+		release := make(chan struct{})
+		defer close(release)
+		p4 := New(func(resolve func(string), reject func(error)) {
+			<-release
+			resolve("slowest")
+		})
+
+		results, err := Some(ctx, 2, p1, p2, p3, p4).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"fast", "slow"}, results)
+	})
+
+	t.Run("Impossible", func(t *testing.T) {
+		ctx := context.Background()
+		p1 := New(func(resolve func(string), reject func(error)) {
+			reject(errors.New("error"))
+		})
+		release := make(chan struct{})
+		defer close(release)
+		p2 := New(func(resolve func(string), reject func(error)) {
+			<-release
+			resolve("slow")
+		})
+
+		_, err := Some(ctx, 2, p1, p2).Await(ctx)
+		var multiErr *MultiError
+		require.ErrorAs(t, err, &multiErr)
+	})
+
+	t.Run("FuncCancelsLeftovers", func(t *testing.T) {
+		ctx := context.Background()
+		canceled := make(chan error, 1)
+		results, err := SomeFunc(ctx, 1,
+			func(ctx context.Context) *Promise[string] {
+				return New(func(resolve func(string), reject func(error)) {
+					resolve("fast")
+				})
+			},
+			func(ctx context.Context) *Promise[string] {
+				return New(func(resolve func(string), reject func(error)) {
+					<-ctx.Done()
+					canceled <- ctx.Err()
+					reject(ctx.Err())
+				})
+			},
+		).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"fast"}, results)
+
+		select {
+		case err := <-canceled:
+			require.ErrorIs(t, err, context.Canceled)
+		case <-time.After(time.Second):
+			t.Fatal("leftover attempt was not canceled")
+		}
+	})
+}
@@ -0,0 +1,313 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by a Breaker that is not accepting calls
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState is the state of a Breaker
+type BreakerState int
+
+const (
+	// StateClosed lets every call through and records its outcome
+	StateClosed BreakerState = iota
+	// StateOpen rejects every call with ErrCircuitOpen
+	StateOpen
+	// StateHalfOpen lets a limited number of probe calls through
+	StateHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerConfig configures a Breaker. Zero values are replaced with defaults.
+type BreakerConfig struct {
+	// Name identifies the breaker in metrics
+	Name string
+	// Window is the length of the rolling window used to compute the failure rate (default 10s)
+	Window time.Duration
+	// Buckets is the number of buckets the window is split into (default 10)
+	Buckets int
+	// MinRequests is the number of calls in the window required before the breaker can trip (default 10)
+	MinRequests int
+	// FailureRatio is the failure rate at which the breaker trips (default 0.5)
+	FailureRatio float64
+	// OpenTimeout is how long the breaker stays open before probing (default 5s).
+	// It also bounds how long probes may take: a breaker still half-open after it opens again.
+	OpenTimeout time.Duration
+	// HalfOpenMaxCalls is the number of probe calls allowed while half-open (default 1)
+	HalfOpenMaxCalls int
+	// IsFailure reports whether an error counts as a failure (default every error).
+	// Calls canceled with context.Canceled are never recorded, they say nothing about the health of the callee.
+	IsFailure func(error) bool
+	// Clock measures the window and the open timeout (default clock if nil)
+	Clock Clock
+	// OnStateChange is called after every state transition.
+	// It runs while the breaker is locked and must not call back into it.
+	OnStateChange func(name string, from, to BreakerState)
+}
+
+type breakerBucket struct {
+	epoch    int64
+	total    int
+	failures int
+}
+
+// Breaker is a circuit breaker for promise-producing functions
+type Breaker struct {
+	cfg BreakerConfig
+
+	mu         sync.Mutex
+	state      BreakerState
+	generation uint64
+	changedAt  time.Time
+	probes     int
+	successes  int
+	buckets    []breakerBucket
+}
+
+// NewBreaker creates a new Breaker in the closed state
+func NewBreaker(cfg BreakerConfig) *Breaker {
+	if cfg.Window <= 0 {
+		cfg.Window = 10 * time.Second
+	}
+	if cfg.Buckets <= 0 {
+		cfg.Buckets = 10
+	}
+	if cfg.MinRequests <= 0 {
+		cfg.MinRequests = 10
+	}
+	if cfg.FailureRatio <= 0 {
+		cfg.FailureRatio = 0.5
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 5 * time.Second
+	}
+	if cfg.HalfOpenMaxCalls <= 0 {
+		cfg.HalfOpenMaxCalls = 1
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = func(err error) bool {
+			return err != nil
+		}
+	}
+	b := &Breaker{
+		cfg:     cfg,
+		buckets: make([]breakerBucket, cfg.Buckets),
+	}
+	setBreakerState(cfg.Name, StateClosed)
+	return b
+}
+
+// State returns the current state of the breaker
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refresh(b.now())
+	return b.state
+}
+
+// Protect wraps fn so that its calls go through the breaker.
+// While the breaker is open the returned function rejects with ErrCircuitOpen without calling fn.
+func Protect[T any](b *Breaker, fn func(ctx context.Context) *Promise[T]) func(ctx context.Context) *Promise[T] {
+	return func(ctx context.Context) *Promise[T] {
+		generation, err := b.allow()
+		if err != nil {
+			return rejected[T](err)
+		}
+
+		p := protectCall(b, generation, ctx, fn)
+		return NewWithPool(func(resolve func(T), reject func(error)) {
+			result, err := p.Await(ctx)
+			b.record(generation, err)
+			if err != nil {
+				reject(err)
+			} else {
+				resolve(result)
+			}
+		}, defaultPool)
+	}
+}
+
+// protectCall calls fn, recording a panic as a failure before passing it on
+func protectCall[T any](b *Breaker, generation uint64, ctx context.Context, fn func(ctx context.Context) *Promise[T]) *Promise[T] {
+	defer func() {
+		if r := recover(); r != nil {
+			b.record(generation, errors.New("panic"))
+			panic(r)
+		}
+	}()
+	return fn(ctx)
+}
+
+// ProtectTask wraps an AsyncTask function so that its calls go through the breaker.
+// While the breaker is open the returned function rejects with ErrCircuitOpen without scheduling fn.
+func ProtectTask[T any](b *Breaker, fn func() (T, error)) func() *Promise[T] {
+	return func() *Promise[T] {
+		generation, err := b.allow()
+		if err != nil {
+			return rejected[T](err)
+		}
+
+		return AsyncTask(func() (result T, err error) {
+			// A panic in fn is still a failure, record it before handlePanic rejects the promise
+			defer func() {
+				if r := recover(); r != nil {
+					b.record(generation, errors.New("panic"))
+					panic(r)
+				}
+			}()
+			result, err = fn()
+			b.record(generation, err)
+			return result, err
+		})
+	}
+}
+
+// allow reports whether a call may proceed and returns the generation it belongs to
+func (b *Breaker) allow() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refresh(b.now())
+	switch b.state {
+	case StateOpen:
+		return 0, ErrCircuitOpen
+	case StateHalfOpen:
+		if b.probes >= b.cfg.HalfOpenMaxCalls {
+			return 0, ErrCircuitOpen
+		}
+		b.probes++
+	}
+	return b.generation, nil
+}
+
+// record stores the outcome of a call started in the given generation
+func (b *Breaker) record(generation uint64, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.refresh(now)
+	if generation != b.generation {
+		// The call started before the last state change, its outcome no longer matters
+		return
+	}
+
+	if errors.Is(err, context.Canceled) {
+		// The caller gave up, free the probe slot without counting the call
+		if b.state == StateHalfOpen {
+			b.probes--
+		}
+		return
+	}
+
+	failed := b.cfg.IsFailure(err)
+	switch b.state {
+	case StateClosed:
+		bucket := b.bucket(now)
+		bucket.total++
+		if failed {
+			bucket.failures++
+		}
+		total, failures := b.counts(now)
+		if total >= b.cfg.MinRequests && float64(failures)/float64(total) >= b.cfg.FailureRatio {
+			b.transition(StateOpen, now)
+		}
+	case StateHalfOpen:
+		if failed {
+			b.transition(StateOpen, now)
+			return
+		}
+		b.successes++
+		if b.successes >= b.cfg.HalfOpenMaxCalls {
+			b.transition(StateClosed, now)
+		}
+	}
+}
+
+func (b *Breaker) now() time.Time {
+	if b.cfg.Clock != nil {
+		return b.cfg.Clock.Now()
+	}
+	return getClock().Now()
+}
+
+// refresh moves an open breaker to half-open once the open timeout has passed,
+// and a half-open one back to open if its probes have not settled by then
+func (b *Breaker) refresh(now time.Time) {
+	if now.Sub(b.changedAt) < b.cfg.OpenTimeout {
+		return
+	}
+	switch b.state {
+	case StateOpen:
+		b.transition(StateHalfOpen, now)
+	case StateHalfOpen:
+		b.transition(StateOpen, now)
+	}
+}
+
+func (b *Breaker) transition(to BreakerState, now time.Time) {
+	from := b.state
+	if from == to {
+		return
+	}
+	b.state = to
+	b.generation++
+	b.probes = 0
+	b.successes = 0
+	b.changedAt = now
+	if to == StateClosed {
+		for i := range b.buckets {
+			b.buckets[i] = breakerBucket{}
+		}
+	}
+
+	observeBreakerStateChange(b.cfg.Name, from, to)
+	if b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(b.cfg.Name, from, to)
+	}
+}
+
+func (b *Breaker) bucketWidth() int64 {
+	width := int64(b.cfg.Window) / int64(len(b.buckets))
+	if width <= 0 {
+		width = 1
+	}
+	return width
+}
+
+func (b *Breaker) bucket(now time.Time) *breakerBucket {
+	epoch := now.UnixNano() / b.bucketWidth()
+	bucket := &b.buckets[epoch%int64(len(b.buckets))]
+	if bucket.epoch != epoch {
+		*bucket = breakerBucket{epoch: epoch}
+	}
+	return bucket
+}
+
+func (b *Breaker) counts(now time.Time) (total, failures int) {
+	epoch := now.UnixNano() / b.bucketWidth()
+	for _, bucket := range b.buckets {
+		if epoch-bucket.epoch < int64(len(b.buckets)) {
+			total += bucket.total
+			failures += bucket.failures
+		}
+	}
+	return total, failures
+}
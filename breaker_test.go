@@ -0,0 +1,145 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreaker(t *testing.T) {
+	t.Run("TripsAndRejects", func(t *testing.T) {
+		ctx := context.Background()
+		calls := 0
+		b := NewBreaker(BreakerConfig{Name: "trips", MinRequests: 2, OpenTimeout: time.Hour})
+		call := ProtectTask(b, func() (int, error) {
+			calls++
+			return 0, errors.New("boom")
+		})
+
+		for i := 0; i < 2; i++ {
+			_, err := call().Await(ctx)
+			require.EqualError(t, err, "boom")
+		}
+		require.Equal(t, StateOpen, b.State())
+
+		_, err := call().Await(ctx)
+		require.ErrorIs(t, err, ErrCircuitOpen)
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("HalfOpenRecovers", func(t *testing.T) {
+		ctx := context.Background()
+		fail := true
+		var transitions []BreakerState
+		b := NewBreaker(BreakerConfig{
+			Name:        "recovers",
+			MinRequests: 1,
+			OpenTimeout: 50 * time.Millisecond,
+			OnStateChange: func(name string, from, to BreakerState) {
+				transitions = append(transitions, to)
+			},
+		})
+		call := Protect(b, func(ctx context.Context) *Promise[string] {
+			return AsyncTask(func() (string, error) {
+				if fail {
+					return "", errors.New("boom")
+				}
+				return "ok", nil
+			})
+		})
+
+		_, err := call(ctx).Await(ctx)
+		require.Error(t, err)
+		require.Equal(t, StateOpen, b.State())
+
+		time.Sleep(60 * time.Millisecond)
+		fail = false
+		result, err := call(ctx).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "ok", result)
+		require.Equal(t, StateClosed, b.State())
+		require.Equal(t, []BreakerState{StateOpen, StateHalfOpen, StateClosed}, transitions)
+	})
+
+	t.Run("PanicCountsAsFailure", func(t *testing.T) {
+		ctx := context.Background()
+		b := NewBreaker(BreakerConfig{Name: "panics", MinRequests: 1, OpenTimeout: time.Hour})
+		call := ProtectTask(b, func() (int, error) {
+			panic("boom")
+		})
+
+		_, err := call().Await(ctx)
+		require.EqualError(t, err, "boom")
+		require.Equal(t, StateOpen, b.State())
+	})
+	t.Run("ProtectPanicCountsAsFailure", func(t *testing.T) {
+		b := NewBreaker(BreakerConfig{Name: "protect-panics", MinRequests: 1, OpenTimeout: time.Hour})
+		call := Protect(b, func(ctx context.Context) *Promise[int] {
+			panic("boom")
+		})
+
+		require.PanicsWithValue(t, "boom", func() {
+			call(context.Background())
+		})
+		require.Equal(t, StateOpen, b.State())
+	})
+
+	t.Run("CanceledProbeIsIgnored", func(t *testing.T) {
+		ctx := context.Background()
+		b := NewBreaker(BreakerConfig{Name: "canceled-probe", MinRequests: 1, OpenTimeout: 30 * time.Millisecond})
+		var outcome error
+		call := ProtectTask(b, func() (int, error) {
+			return 1, outcome
+		})
+
+		outcome = errors.New("boom")
+		_, err := call().Await(ctx)
+		require.Error(t, err)
+		require.Equal(t, StateOpen, b.State())
+
+		time.Sleep(40 * time.Millisecond)
+		outcome = context.Canceled
+		_, err = call().Await(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, StateHalfOpen, b.State())
+
+		outcome = nil
+		_, err = call().Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, StateClosed, b.State())
+	})
+
+	t.Run("StuckProbeReopens", func(t *testing.T) {
+		ctx := context.Background()
+		b := NewBreaker(BreakerConfig{Name: "stuck-probe", MinRequests: 1, OpenTimeout: 30 * time.Millisecond})
+		release := make(chan struct{})
+		defer close(release)
+		fail := true
+		call := Protect(b, func(ctx context.Context) *Promise[int] {
+			if fail {
+				return rejected[int](errors.New("boom"))
+			}
+			return New(func(resolve func(int), reject func(error)) {
+				<-release
+				resolve(1)
+			})
+		})
+
+		_, err := call(ctx).Await(ctx)
+		require.Error(t, err)
+		time.Sleep(40 * time.Millisecond)
+		fail = false
+		call(ctx)
+		require.Equal(t, StateHalfOpen, b.State())
+		_, err = call(ctx).Await(ctx)
+		require.ErrorIs(t, err, ErrCircuitOpen)
+
+		time.Sleep(40 * time.Millisecond)
+		require.Equal(t, StateOpen, b.State())
+		time.Sleep(40 * time.Millisecond)
+		require.Equal(t, StateHalfOpen, b.State())
+	})
+}
@@ -0,0 +1,177 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrBulkheadFull is returned for tasks that find every slot and queue place of their Bulkhead taken
+	ErrBulkheadFull = errors.New("bulkhead is full")
+	// ErrUnknownBulkhead is returned for promises created WithBulkhead with a name that has not been registered
+	ErrUnknownBulkhead = errors.New("unknown bulkhead")
+)
+
+// BulkheadConfig configures a Bulkhead
+type BulkheadConfig struct {
+	// MaxConcurrent is the number of tasks that may run at once (default 10)
+	MaxConcurrent int
+	// MaxQueue is the number of tasks that may wait for a slot. Tasks beyond it are rejected with ErrBulkheadFull.
+	MaxQueue int
+}
+
+// Bulkhead is a Pool with its own concurrency and queue limit, so that one slow dependency
+// can only use up its own share of the work and not starve everything else.
+// Promises bound their time in the queue with WithContext.
+type Bulkhead struct {
+	name  string
+	pool  Pool
+	queue *admissionQueue
+}
+
+// NewBulkhead creates a Bulkhead that runs admitted tasks on pool, for example one made with FromAntsPool or FromConcPool.
+// The name identifies the bulkhead in metrics.
+func NewBulkhead(name string, pool Pool, cfg BulkheadConfig) *Bulkhead {
+	if pool == nil {
+		panic("pool must not be nil")
+	}
+	if cfg.MaxConcurrent <= 0 {
+		cfg.MaxConcurrent = 10
+	}
+	if cfg.MaxQueue < 0 {
+		cfg.MaxQueue = 0
+	}
+	b := &Bulkhead{
+		name: name,
+		pool: pool,
+		queue: &admissionQueue{
+			capacity:   int64(cfg.MaxConcurrent),
+			maxWaiting: cfg.MaxQueue,
+			full:       fmt.Errorf("%w: %s", ErrBulkheadFull, name),
+			observe: func(active int64, queued int) {
+				setBulkheadActive(name, int(active))
+				setBulkheadQueued(name, queued)
+			},
+		},
+	}
+	setBulkheadActive(name, 0)
+	setBulkheadQueued(name, 0)
+	return b
+}
+
+// Go runs f once the bulkhead has a free slot. It panics with ErrBulkheadFull if the queue is full,
+// promises running on the bulkhead are rejected instead. If the underlying pool refuses f after it
+// has waited in the queue, f is dropped and logged.
+func (b *Bulkhead) Go(f func()) {
+	goAdmitted(b, b.admit, f)
+}
+
+// Name returns the name of the bulkhead
+func (b *Bulkhead) Name() string {
+	return b.name
+}
+
+func (b *Bulkhead) String() string {
+	return fmt.Sprintf("bulkhead %s", b.name)
+}
+
+// admit takes one slot per task, whatever its weight
+func (b *Bulkhead) admit(ctx context.Context, _ int64, run func(), fail func(error)) {
+	b.queue.acquire(ctx, 1, func() {
+		b.queue.start(b.pool, 1, run, fail)
+	}, func(err error) {
+		if errors.Is(err, ErrBulkheadFull) {
+			incrementBulkheadRejected(b.name)
+		}
+		fail(err)
+	})
+}
+
+// BulkheadRegistry holds named bulkheads
+type BulkheadRegistry struct {
+	mu        sync.RWMutex
+	bulkheads map[string]*Bulkhead
+}
+
+// NewBulkheadRegistry creates a new empty BulkheadRegistry
+func NewBulkheadRegistry() *BulkheadRegistry {
+	return &BulkheadRegistry{bulkheads: make(map[string]*Bulkhead)}
+}
+
+// Register creates and adds a Bulkhead with the given name. It fails if the name is already taken.
+func (r *BulkheadRegistry) Register(name string, pool Pool, cfg BulkheadConfig) (*Bulkhead, error) {
+	if name == "" {
+		return nil, errors.New("bulkhead name must not be empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.bulkheads[name]; ok {
+		return nil, fmt.Errorf("bulkhead %q already exists", name)
+	}
+	b := NewBulkhead(name, pool, cfg)
+	r.bulkheads[name] = b
+	return b, nil
+}
+
+// Get returns the Bulkhead with the given name, if it has been registered
+func (r *BulkheadRegistry) Get(name string) (*Bulkhead, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	b, ok := r.bulkheads[name]
+	return b, ok
+}
+
+// Remove deletes the Bulkhead with the given name from the registry.
+// Promises already running on it are not affected.
+func (r *BulkheadRegistry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.bulkheads, name)
+}
+
+var defaultBulkheads = NewBulkheadRegistry()
+
+// RegisterBulkhead adds a Bulkhead to the default registry used by WithBulkhead
+func RegisterBulkhead(name string, pool Pool, cfg BulkheadConfig) (*Bulkhead, error) {
+	return defaultBulkheads.Register(name, pool, cfg)
+}
+
+// WithBulkhead runs the task of the Promise on the named Bulkhead of the default registry.
+// The Promise rejects with ErrBulkheadFull if the bulkhead is saturated, or ErrUnknownBulkhead
+// if no bulkhead has been registered with that name.
+func WithBulkhead(name string) Option {
+	return defaultBulkheads.With(name)
+}
+
+// With runs the task of the Promise on the named Bulkhead of the registry. See WithBulkhead.
+func (r *BulkheadRegistry) With(name string) Option {
+	return func(o *options) {
+		if b, ok := r.Get(name); ok {
+			o.pool = b
+		} else {
+			o.pool = unknownBulkhead(name)
+		}
+	}
+}
+
+// unknownBulkhead is the pool of promises that asked for a bulkhead that does not exist
+type unknownBulkhead string
+
+func (u unknownBulkhead) Go(func()) {
+	panic(u.err())
+}
+
+func (u unknownBulkhead) String() string {
+	return fmt.Sprintf("bulkhead %s", string(u))
+}
+
+func (u unknownBulkhead) admit(_ context.Context, _ int64, _ func(), fail func(error)) {
+	fail(u.err())
+}
+
+func (u unknownBulkhead) err() error {
+	return fmt.Errorf("%w: %q", ErrUnknownBulkhead, string(u))
+}
@@ -0,0 +1,145 @@
+package promise4g
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/panjf2000/ants/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkhead(t *testing.T) {
+	t.Run("LimitsAndRejects", func(t *testing.T) {
+		ctx := context.Background()
+		antsPool, err := ants.NewPool(10)
+		require.NoError(t, err)
+		defer antsPool.Release()
+		_, err = RegisterBulkhead("payments-test", FromAntsPool(antsPool), BulkheadConfig{MaxConcurrent: 2, MaxQueue: 1})
+		require.NoError(t, err)
+		defer defaultBulkheads.Remove("payments-test")
+
+		release := make(chan struct{})
+		var tracker peakTracker
+		task := func() *Promise[int] {
+			return NewWithOptions(func(resolve func(int), reject func(error)) {
+				done := tracker.trackPeak(1)
+				<-release
+				done()
+				resolve(1)
+			}, WithBulkhead("payments-test"))
+		}
+
+		first, second, queued := task(), task(), task()
+		_, err = task().Await(ctx)
+		require.ErrorIs(t, err, ErrBulkheadFull)
+
+		close(release)
+		results, err := All(ctx, first, second, queued).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 1, 1}, results)
+		require.LessOrEqual(t, tracker.max(), int64(2))
+	})
+
+	t.Run("Isolation", func(t *testing.T) {
+		ctx := context.Background()
+		registry := NewBulkheadRegistry()
+		_, err := registry.Register("slow", newDefaultPool(), BulkheadConfig{MaxConcurrent: 1})
+		require.NoError(t, err)
+		_, err = registry.Register("fast", newDefaultPool(), BulkheadConfig{MaxConcurrent: 1})
+		require.NoError(t, err)
+
+		release := make(chan struct{})
+		defer close(release)
+		NewWithOptions(func(resolve func(int), reject func(error)) {
+			<-release
+			resolve(1)
+		}, registry.With("slow"))
+
+		_, err = NewWithOptions(func(resolve func(int), reject func(error)) {
+			resolve(1)
+		}, registry.With("slow")).Await(ctx)
+		require.ErrorIs(t, err, ErrBulkheadFull)
+
+		result, err := NewWithOptions(func(resolve func(int), reject func(error)) {
+			resolve(2)
+		}, registry.With("fast")).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, result)
+
+		_, err = NewWithOptions(func(resolve func(int), reject func(error)) {
+			resolve(3)
+		}, WithBulkhead("fast")).Await(ctx)
+		require.ErrorIs(t, err, ErrUnknownBulkhead)
+	})
+
+	t.Run("QueueWaitCanceled", func(t *testing.T) {
+		b := NewBulkhead("queue-canceled", newDefaultPool(), BulkheadConfig{MaxConcurrent: 1, MaxQueue: 1})
+		release := make(chan struct{})
+		defer close(release)
+		NewWithPool(func(resolve func(int), reject func(error)) {
+			<-release
+			resolve(1)
+		}, b)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := NewWithOptions(func(resolve func(int), reject func(error)) {
+			resolve(2)
+		}, WithPool(b), WithContext(ctx)).Await(context.Background())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("PoolClosedWhileQueued", func(t *testing.T) {
+		ctx := context.Background()
+		pool := NewManagedPool(nil)
+		b := NewBulkhead("closing-test", pool, BulkheadConfig{MaxConcurrent: 1, MaxQueue: 1})
+
+		release := make(chan struct{})
+		running := NewWithPool(func(resolve func(int), reject func(error)) {
+			<-release
+			resolve(1)
+		}, b)
+		queued := NewWithPool(func(resolve func(int), reject func(error)) {
+			resolve(2)
+		}, b)
+
+		shutdown := make(chan error, 1)
+		go func() {
+			shutdown <- pool.Shutdown(ctx)
+		}()
+		require.Eventually(t, pool.Closed, time.Second, time.Millisecond)
+		close(release)
+		require.NoError(t, <-shutdown)
+
+		result, err := running.Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, result)
+		_, err = queued.Await(ctx)
+		require.ErrorIs(t, err, ErrPoolClosed)
+
+		// The slot of the refused task has been given back
+		_, err = NewWithPool(func(resolve func(int), reject func(error)) {
+			resolve(3)
+		}, b).Await(ctx)
+		require.ErrorIs(t, err, ErrPoolClosed)
+		b.queue.mu.Lock()
+		defer b.queue.mu.Unlock()
+		require.Zero(t, b.queue.used)
+	})
+
+	t.Run("DuplicateName", func(t *testing.T) {
+		registry := NewBulkheadRegistry()
+		_, err := registry.Register("dup", newDefaultPool(), BulkheadConfig{})
+		require.NoError(t, err)
+		_, err = registry.Register("dup", newDefaultPool(), BulkheadConfig{})
+		require.Error(t, err)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		_, err := NewWithOptions(func(resolve func(int), reject func(error)) {
+			resolve(1)
+		}, WithBulkhead("does-not-exist")).Await(context.Background())
+		require.ErrorIs(t, err, ErrUnknownBulkhead)
+	})
+}
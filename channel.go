@@ -0,0 +1,57 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrChannelClosed is returned when a channel is closed before it delivers a value
+var ErrChannelClosed = errors.New("channel closed")
+
+// FromChan creates a new Promise that resolves with the first value received from ch.
+// It rejects with ErrChannelClosed if ch is closed first, or with the context error if ctx is done first.
+func FromChan[T any](ctx context.Context, ch <-chan T) *Promise[T] {
+	return New(func(resolve func(T), reject func(error)) {
+		select {
+		case <-ctx.Done():
+			reject(ctx.Err())
+		case value, ok := <-ch:
+			if !ok {
+				reject(ErrChannelClosed)
+				return
+			}
+			resolve(value)
+		}
+	})
+}
+
+// FromErrChan creates a new Promise that settles with the first Result received from ch.
+// It rejects with ErrChannelClosed if ch is closed first, or with the context error if ctx is done first.
+func FromErrChan[T any](ctx context.Context, ch <-chan Result[T]) *Promise[T] {
+	return New(func(resolve func(T), reject func(error)) {
+		select {
+		case <-ctx.Done():
+			reject(ctx.Err())
+		case result, ok := <-ch:
+			if !ok {
+				reject(ErrChannelClosed)
+				return
+			}
+			if result.Err != nil {
+				reject(result.Err)
+				return
+			}
+			resolve(result.Value)
+		}
+	})
+}
+
+// Chan returns a channel that receives the outcome of the Promise once it settles.
+// The channel is buffered and receives exactly one value, so it can be used in a select statement.
+func (p *Promise[T]) Chan() <-chan Result[T] {
+	ch := make(chan Result[T], 1)
+	p.OnSettle(func(value T, err error) {
+		ch <- Result[T]{Value: value, Err: err}
+	})
+	return ch
+}
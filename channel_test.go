@@ -0,0 +1,59 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromChan(t *testing.T) {
+	t.Run("Receive", func(t *testing.T) {
+		ctx := context.Background()
+		ch := make(chan string, 1)
+		ch <- "one"
+		result, err := FromChan(ctx, ch).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "one", result)
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		ctx := context.Background()
+		ch := make(chan string)
+		close(ch)
+		_, err := FromChan(ctx, ch).Await(ctx)
+		require.ErrorIs(t, err, ErrChannelClosed)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := FromChan(ctx, make(chan string)).Await(context.Background())
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestFromErrChan(t *testing.T) {
+	ctx := context.Background()
+	ch := make(chan Result[int], 1)
+	ch <- Result[int]{Err: errors.New("boom")}
+	_, err := FromErrChan(ctx, ch).Await(ctx)
+	require.EqualError(t, err, "boom")
+}
+
+func TestPromise_Chan(t *testing.T) {
+	p := New(func(resolve func(int), reject func(error)) {
+		time.Sleep(10 * time.Millisecond)
+		resolve(1)
+	})
+
+	select {
+	case result := <-p.Chan():
+		require.NoError(t, result.Err)
+		require.Equal(t, 1, result.Value)
+	case <-time.After(time.Second):
+		t.Fatal("promise did not settle")
+	}
+}
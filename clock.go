@@ -0,0 +1,89 @@
+package promise4g
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Clock tells the time and creates timers for everything in the library that depends on time.
+// The clock of a promise can be set with WithClock, the clock of a combinator with ContextWithClock,
+// and the default one with SetClock.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) Timer
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a single event created by a Clock, see time.Timer
+type Timer interface {
+	// C returns the channel the time is delivered on. It is nil for timers created by AfterFunc.
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return realTimer{time.AfterFunc(d, f)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+type clockHolder struct {
+	Clock
+}
+
+var currentClock atomic.Value // clockHolder
+
+func init() {
+	currentClock.Store(clockHolder{realClock{}})
+}
+
+// SetClock replaces the default clock used by the library. A nil clock restores the real one.
+func SetClock(c Clock) {
+	if c == nil {
+		c = realClock{}
+	}
+	currentClock.Store(clockHolder{c})
+}
+
+func getClock() Clock {
+	return currentClock.Load().(clockHolder).Clock
+}
+
+type clockKey struct{}
+
+// ContextWithClock returns a copy of ctx carrying the given clock.
+// Functions that take a context use it instead of the default clock.
+func ContextWithClock(ctx context.Context, c Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, c)
+}
+
+// ClockFromContext returns the clock carried by ctx, or the default clock
+func ClockFromContext(ctx context.Context) Clock {
+	if c, ok := ctx.Value(clockKey{}).(Clock); ok && c != nil {
+		return c
+	}
+	return getClock()
+}
@@ -0,0 +1,104 @@
+package promise4g
+
+import (
+	"sync"
+	"time"
+)
+
+// Debounce returns a function that coalesces calls for the same key into a single call of fn.
+// fn runs once no call for the key has been made for wait, and every call made in the meantime
+// gets the same Promise, which settles with the outcome of that single run.
+// The options apply to the promises it returns; their clock, from WithClock or the context given WithContext,
+// also measures wait, which must be positive.
+func Debounce[K comparable, T any](fn func(key K) (T, error), wait time.Duration, opts ...Option) func(key K) *Promise[T] {
+	if fn == nil {
+		panic("fn must not be nil")
+	}
+	if wait <= 0 {
+		panic("wait must be positive")
+	}
+
+	type entry struct {
+		p     *Promise[T]
+		timer Timer
+	}
+	var mu sync.Mutex
+	pending := make(map[K]*entry)
+
+	return func(key K) *Promise[T] {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if e, ok := pending[key]; ok {
+			e.timer.Reset(wait)
+			return e.p
+		}
+
+		o := newOptions(opts)
+		e := &entry{p: newPromise[T](o)}
+		e.timer = o.clock.AfterFunc(wait, func() {
+			mu.Lock()
+			if pending[key] != e {
+				// A Reset raced with the timer firing, the entry has already run
+				mu.Unlock()
+				return
+			}
+			delete(pending, key)
+			mu.Unlock()
+
+			e.p.submit(o.ctx, o.pool, o.weight, func(resolve func(T), reject func(error)) {
+				result, err := fn(key)
+				if err != nil {
+					reject(err)
+				} else {
+					resolve(result)
+				}
+			})
+		})
+		pending[key] = e
+		return e.p
+	}
+}
+
+// Throttle returns a function that calls fn at most once per interval for the same key.
+// The first call runs fn right away, and calls made within interval after it get the same Promise.
+// The options apply to the promises it returns; their clock, from WithClock or the context given WithContext,
+// also measures interval, which must be positive.
+func Throttle[K comparable, T any](fn func(key K) (T, error), interval time.Duration, opts ...Option) func(key K) *Promise[T] {
+	if fn == nil {
+		panic("fn must not be nil")
+	}
+	if interval <= 0 {
+		panic("interval must be positive")
+	}
+
+	var mu sync.Mutex
+	last := make(map[K]*Promise[T])
+
+	return func(key K) *Promise[T] {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if p, ok := last[key]; ok {
+			return p
+		}
+
+		p := NewWithOptions(func(resolve func(T), reject func(error)) {
+			result, err := fn(key)
+			if err != nil {
+				reject(err)
+			} else {
+				resolve(result)
+			}
+		}, opts...)
+		last[key] = p
+		p.clock.AfterFunc(interval, func() {
+			mu.Lock()
+			defer mu.Unlock()
+			if last[key] == p {
+				delete(last, key)
+			}
+		})
+		return p
+	}
+}
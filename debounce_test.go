@@ -0,0 +1,85 @@
+package promise4g_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hoanguyenkh/promise4g"
+	"github.com/hoanguyenkh/promise4g/promisetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebounce(t *testing.T) {
+	ctx := context.Background()
+	clock := promisetest.NewFakeClock(time.Unix(0, 0))
+	promise4g.SetClock(clock)
+	defer promise4g.SetClock(nil)
+
+	var calls atomic.Int32
+	load := promise4g.Debounce(func(key string) (string, error) {
+		calls.Add(1)
+		return "value of " + key, nil
+	}, 30*time.Millisecond)
+
+	p1 := load("a")
+	clock.Advance(10 * time.Millisecond)
+	p2 := load("a")
+	p3 := load("b")
+	require.Same(t, p1, p2)
+	require.NotSame(t, p1, p3)
+
+	clock.Advance(29 * time.Millisecond)
+	require.Equal(t, int32(0), calls.Load())
+	clock.Advance(time.Millisecond)
+
+	result, err := p1.Await(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "value of a", result)
+	result, err = p3.Await(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "value of b", result)
+	require.Equal(t, int32(2), calls.Load())
+
+	p4 := load("a")
+	require.NotSame(t, p1, p4)
+	clock.Advance(30 * time.Millisecond)
+	_, err = p4.Await(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), calls.Load())
+
+	require.PanicsWithValue(t, "wait must be positive", func() {
+		promise4g.Debounce(func(string) (string, error) {
+			return "", nil
+		}, 0)
+	})
+}
+
+func TestThrottle(t *testing.T) {
+	ctx := context.Background()
+	clock := promisetest.NewFakeClock(time.Unix(0, 0))
+	var calls atomic.Int32
+	refresh := promise4g.Throttle(func(key int) (int32, error) {
+		return calls.Add(1), nil
+	}, 30*time.Millisecond, promise4g.WithClock(clock))
+
+	p1 := refresh(1)
+	result, err := p1.Await(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), result)
+
+	clock.Advance(29 * time.Millisecond)
+	require.Same(t, p1, refresh(1))
+
+	clock.Advance(time.Millisecond)
+	result, err = refresh(1).Await(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), result)
+
+	require.PanicsWithValue(t, "interval must be positive", func() {
+		promise4g.Throttle(func(int) (int, error) {
+			return 0, nil
+		}, 0)
+	})
+}
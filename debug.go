@@ -0,0 +1,192 @@
+package promise4g
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	debugEnabled  atomic.Bool
+	debugNextID   atomic.Uint64
+	debugRegistry sync.Map // map[uint64]*pendingRecord
+)
+
+type pendingRecord struct {
+	name    string
+	pool    string
+	clock   Clock
+	created time.Time
+	stack   []uintptr
+}
+
+// PendingPromise describes a promise that has not settled yet
+type PendingPromise struct {
+	ID      uint64
+	Name    string
+	Pool    string
+	Created time.Time
+	Age     time.Duration
+	// Stack is the formatted stack of the goroutine that created the promise
+	Stack string
+}
+
+// EnableDebugRegistry turns recording of pending promises on or off.
+// Only promises created while the registry is enabled are recorded.
+func EnableDebugRegistry(enabled bool) {
+	debugEnabled.Store(enabled)
+}
+
+// registerPending records a new pending promise and returns its id, or 0 if the registry is disabled
+func registerPending(o *options) uint64 {
+	if !debugEnabled.Load() {
+		return 0
+	}
+
+	stack := make([]uintptr, 64)
+	// Skip runtime.Callers, registerPending and newPromise
+	stack = stack[:runtime.Callers(3, stack)]
+	id := debugNextID.Add(1)
+	record := &pendingRecord{
+		name:    o.name,
+		clock:   o.clock,
+		created: o.clock.Now(),
+		stack:   stack,
+	}
+	if o.pool != nil {
+		record.pool = poolName(o.pool)
+	}
+	debugRegistry.Store(id, record)
+	return id
+}
+
+func unregisterPending(id uint64) {
+	if id != 0 {
+		debugRegistry.Delete(id)
+	}
+}
+
+// PendingPromises returns the promises recorded by the debug registry that have not settled yet, oldest first
+func PendingPromises() []PendingPromise {
+	var pending []PendingPromise
+	debugRegistry.Range(func(key, value any) bool {
+		record := value.(*pendingRecord)
+		pending = append(pending, PendingPromise{
+			ID:      key.(uint64),
+			Name:    record.name,
+			Pool:    record.pool,
+			Created: record.created,
+			Age:     record.clock.Now().Sub(record.created),
+			Stack:   formatStack(record.stack),
+		})
+		return true
+	})
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].ID < pending[j].ID
+	})
+	return pending
+}
+
+func formatStack(stack []uintptr) string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// DumpPending writes every pending promise recorded by the debug registry to w, oldest first
+func DumpPending(w io.Writer) error {
+	return dumpPending(w, PendingPromises())
+}
+
+func dumpPending(w io.Writer, pending []PendingPromise) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d pending promises\n", len(pending))
+	for _, p := range pending {
+		name := p.Name
+		if name == "" {
+			name = "unnamed"
+		}
+		fmt.Fprintf(&b, "\npromise %d %q [pool %s, age %s]:\n%s", p.ID, name, p.Pool, p.Age.Round(time.Millisecond), p.Stack)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// DebugHandler returns an http.Handler that lists the pending promises recorded by the debug registry.
+// The optional min_age query parameter, such as ?min_age=5s, only lists promises at least that old.
+func DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var minAge time.Duration
+		if s := r.URL.Query().Get("min_age"); s != "" {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid min_age: %v", err), http.StatusBadRequest)
+				return
+			}
+			minAge = d
+		}
+
+		var pending []PendingPromise
+		for _, p := range PendingPromises() {
+			if p.Age >= minAge {
+				pending = append(pending, p)
+			}
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_ = dumpPending(w, pending)
+	})
+}
+
+// TestingT is the subset of testing.TB used by VerifyNoPendingPromises
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Cleanup(func())
+}
+
+// VerifyNoPendingPromises enables the debug registry for the rest of the test and fails the test
+// if any promise created after this call is still pending when the test ends.
+// Promises get a short grace period to settle before the test fails.
+func VerifyNoPendingPromises(t TestingT) {
+	t.Helper()
+	wasEnabled := debugEnabled.Swap(true)
+	mark := debugNextID.Load()
+
+	t.Cleanup(func() {
+		t.Helper()
+		defer debugEnabled.Store(wasEnabled)
+
+		var leaked []PendingPromise
+		deadline := time.Now().Add(time.Second)
+		for {
+			leaked = leaked[:0]
+			for _, p := range PendingPromises() {
+				if p.ID > mark {
+					leaked = append(leaked, p)
+				}
+			}
+			if len(leaked) == 0 || time.Now().After(deadline) {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if len(leaked) > 0 {
+			var b strings.Builder
+			_ = dumpPending(&b, leaked)
+			t.Errorf("found pending promises: %s", b.String())
+		}
+	})
+}
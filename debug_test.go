@@ -0,0 +1,82 @@
+package promise4g
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeT struct {
+	errors   []string
+	cleanups []func()
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) Cleanup(fn func()) {
+	f.cleanups = append(f.cleanups, fn)
+}
+
+func (f *fakeT) finish() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func TestDebugRegistry(t *testing.T) {
+	t.Run("DumpPending", func(t *testing.T) {
+		EnableDebugRegistry(true)
+		defer EnableDebugRegistry(false)
+
+		release := make(chan struct{})
+		p := NewWithOptions(func(resolve func(int), reject func(error)) {
+			<-release
+			resolve(1)
+		}, WithName("stuck-import"))
+
+		var dump strings.Builder
+		require.NoError(t, DumpPending(&dump))
+		require.Contains(t, dump.String(), `"stuck-import" [pool default`)
+		require.Contains(t, dump.String(), "TestDebugRegistry")
+
+		rec := httptest.NewRecorder()
+		DebugHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/promises?min_age=1h", nil))
+		require.NotContains(t, rec.Body.String(), "stuck-import")
+
+		close(release)
+		_, err := p.Await(context.Background())
+		require.NoError(t, err)
+		for _, pending := range PendingPromises() {
+			require.NotEqual(t, "stuck-import", pending.Name)
+		}
+	})
+
+	t.Run("VerifyNoPendingPromises", func(t *testing.T) {
+		ft := &fakeT{}
+		VerifyNoPendingPromises(ft)
+		release := make(chan struct{})
+		defer close(release)
+		New(func(resolve func(int), reject func(error)) {
+			<-release
+		})
+		ft.finish()
+		require.Len(t, ft.errors, 1)
+		require.Contains(t, ft.errors[0], "1 pending promises")
+	})
+
+	t.Run("VerifyNoPendingPromisesClean", func(t *testing.T) {
+		VerifyNoPendingPromises(t)
+		_, err := New(func(resolve func(int), reject func(error)) {
+			resolve(1)
+		}).Await(context.Background())
+		require.NoError(t, err)
+	})
+}
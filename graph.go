@@ -0,0 +1,343 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrGraphCycle is returned by Graph.Run when the nodes depend on each other in a cycle
+	ErrGraphCycle = errors.New("graph has a cycle")
+	// ErrNodeSkipped is recorded for nodes that did not run because a dependency failed
+	ErrNodeSkipped = errors.New("node skipped")
+)
+
+// Graph is a set of named steps that depend on the outputs of other steps
+type Graph struct {
+	mu    sync.Mutex
+	pool  Pool
+	nodes map[string]*graphNode
+	order []string
+}
+
+type graphNode struct {
+	name string
+	deps []string
+	fn   func(ctx context.Context, in *GraphResults) (any, error)
+}
+
+// NewGraph creates a new empty Graph
+func NewGraph() *Graph {
+	return NewGraphWithPool(defaultPool)
+}
+
+// NewGraphWithPool creates a new empty Graph whose nodes run on the given pool
+func NewGraphWithPool(pool Pool) *Graph {
+	if pool == nil {
+		panic("pool must not be nil")
+	}
+	return &Graph{
+		pool:  pool,
+		nodes: make(map[string]*graphNode),
+	}
+}
+
+// Add registers a node that runs fn once all of its dependencies have resolved.
+// fn reads the outputs of its dependencies from in, for example with GraphValue.
+func (g *Graph) Add(name string, deps []string, fn func(ctx context.Context, in *GraphResults) (any, error)) error {
+	if name == "" {
+		return errors.New("node name must not be empty")
+	}
+	if fn == nil {
+		return fmt.Errorf("node %q: fn must not be nil", name)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.nodes[name]; ok {
+		return fmt.Errorf("node %q already exists", name)
+	}
+	g.nodes[name] = &graphNode{
+		name: name,
+		deps: append([]string(nil), deps...),
+		fn:   fn,
+	}
+	g.order = append(g.order, name)
+	return nil
+}
+
+// AddNode registers a node with a typed output. See Graph.Add.
+func AddNode[T any](g *Graph, name string, deps []string, fn func(ctx context.Context, in *GraphResults) (T, error)) error {
+	if fn == nil {
+		return fmt.Errorf("node %q: fn must not be nil", name)
+	}
+	return g.Add(name, deps, func(ctx context.Context, in *GraphResults) (any, error) {
+		return fn(ctx, in)
+	})
+}
+
+// Run starts every node as a promise once its inputs have resolved.
+// Nodes downstream of a failure are skipped. The returned promise resolves with the results of all nodes,
+// or rejects with a *GraphError holding them if any node failed or was skipped because ctx was done. It rejects with ErrGraphCycle
+// or an unknown dependency error without running anything if the graph is invalid.
+func (g *Graph) Run(ctx context.Context) *Promise[*GraphResults] {
+	g.mu.Lock()
+	nodes := make([]*graphNode, 0, len(g.order))
+	for _, name := range g.order {
+		nodes = append(nodes, g.nodes[name])
+	}
+	g.mu.Unlock()
+
+	sorted, err := topologicalSort(nodes)
+	if err != nil {
+		return rejected[*GraphResults](err)
+	}
+
+	results := newGraphResults(sorted)
+	promises := make(map[string]*Promise[any], len(sorted))
+	all := make([]*Promise[any], 0, len(sorted))
+	for _, node := range sorted {
+		deps := make([]*Promise[any], len(node.deps))
+		for i, dep := range node.deps {
+			deps[i] = promises[dep]
+		}
+		p := g.runNode(ctx, node, deps, results)
+		promises[node.name] = p
+		all = append(all, p)
+	}
+
+	return NewWithPool(func(resolve func(*GraphResults), reject func(error)) {
+		for _, p := range all {
+			_, _ = p.Await(context.Background())
+		}
+		if err := results.Err(); err != nil {
+			reject(err)
+			return
+		}
+		resolve(results)
+	}, g.pool)
+}
+
+func (g *Graph) runNode(ctx context.Context, node *graphNode, deps []*Promise[any], results *GraphResults) *Promise[any] {
+	return NewWithPool(func(resolve func(any), reject func(error)) {
+		for i, dep := range deps {
+			if _, err := dep.Await(ctx); err != nil {
+				if ctx.Err() == nil {
+					err = fmt.Errorf("%w: dependency %q failed", ErrNodeSkipped, node.deps[i])
+				}
+				results.set(node.name, NodeResult{Err: err, Skipped: true})
+				reject(err)
+				return
+			}
+		}
+
+		clock := ClockFromContext(ctx)
+		start := clock.Now()
+		value, err := callNode(ctx, node, results)
+		results.set(node.name, NodeResult{
+			Value:    value,
+			Err:      err,
+			Start:    start,
+			Duration: clock.Now().Sub(start),
+		})
+		if err != nil {
+			reject(err)
+		} else {
+			resolve(value)
+		}
+	}, g.pool)
+}
+
+// callNode runs the node function, turning a panic into an error so that it is recorded like any other failure
+func callNode(ctx context.Context, node *graphNode, in *GraphResults) (value any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicToError(r)
+		}
+	}()
+	return node.fn(ctx, in)
+}
+
+// topologicalSort orders nodes so that every node comes after its dependencies
+func topologicalSort(nodes []*graphNode) ([]*graphNode, error) {
+	byName := make(map[string]*graphNode, len(nodes))
+	for _, node := range nodes {
+		byName[node.name] = node
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(nodes))
+	sorted := make([]*graphNode, 0, len(nodes))
+	var visit func(node *graphNode, path []string) error
+	visit = func(node *graphNode, path []string) error {
+		switch state[node.name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %s", ErrGraphCycle, strings.Join(append(path, node.name), " -> "))
+		}
+		state[node.name] = visiting
+		for _, dep := range node.deps {
+			depNode, ok := byName[dep]
+			if !ok {
+				return fmt.Errorf("node %q depends on unknown node %q", node.name, dep)
+			}
+			if err := visit(depNode, append(path, node.name)); err != nil {
+				return err
+			}
+		}
+		state[node.name] = visited
+		sorted = append(sorted, node)
+		return nil
+	}
+
+	for _, node := range nodes {
+		if err := visit(node, nil); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// NodeResult is the outcome of a single Graph node
+type NodeResult struct {
+	Value    any
+	Err      error
+	Skipped  bool
+	Start    time.Time
+	Duration time.Duration
+}
+
+// GraphResults holds the outcome of every node of a Graph run
+type GraphResults struct {
+	mu      sync.RWMutex
+	nodes   []*graphNode
+	results map[string]NodeResult
+}
+
+func newGraphResults(nodes []*graphNode) *GraphResults {
+	return &GraphResults{
+		nodes:   nodes,
+		results: make(map[string]NodeResult, len(nodes)),
+	}
+}
+
+func (r *GraphResults) set(name string, result NodeResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results[name] = result
+}
+
+// Node returns the outcome of the named node, if it has settled
+func (r *GraphResults) Node(name string) (NodeResult, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result, ok := r.results[name]
+	return result, ok
+}
+
+// Err returns a *GraphError if any node failed or did not run because the context was done, or nil
+func (r *GraphResults) Err() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	errs := make(map[string]error)
+	for name, result := range r.results {
+		// Nodes skipped for a failed dependency are covered by the error of that dependency
+		if result.Err != nil && !errors.Is(result.Err, ErrNodeSkipped) {
+			errs[name] = result.Err
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &GraphError{Results: r, Errors: errs}
+}
+
+// WriteDOT writes the graph in DOT format, labelling every node with its status and duration
+func (r *GraphResults) WriteDOT(w io.Writer) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var b strings.Builder
+	b.WriteString("digraph promises {\n")
+	for _, node := range r.nodes {
+		result, ok := r.results[node.name]
+		label, color := node.name+"\\npending", "gray"
+		switch {
+		case !ok:
+		case result.Skipped:
+			label, color = node.name+"\\nskipped", "gray"
+		case result.Err != nil:
+			label, color = fmt.Sprintf("%s\\nfailed %s", node.name, result.Duration), "red"
+		default:
+			label, color = fmt.Sprintf("%s\\n%s", node.name, result.Duration), "green"
+		}
+		fmt.Fprintf(&b, "\t%q [label=%q, color=%s];\n", node.name, label, color)
+	}
+	for _, node := range r.nodes {
+		for _, dep := range node.deps {
+			fmt.Fprintf(&b, "\t%q -> %q;\n", dep, node.name)
+		}
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// GraphValue returns the output of the named node as a T
+func GraphValue[T any](r *GraphResults, name string) (T, error) {
+	var t T
+	result, ok := r.Node(name)
+	if !ok {
+		return t, fmt.Errorf("node %q has no result", name)
+	}
+	if result.Err != nil {
+		return t, result.Err
+	}
+	value, ok := result.Value.(T)
+	if !ok && result.Value != nil {
+		return t, fmt.Errorf("node %q: value is %T, not %T", name, result.Value, t)
+	}
+	return value, nil
+}
+
+// GraphError is returned by Graph.Run when one or more nodes failed
+type GraphError struct {
+	Results *GraphResults
+	Errors  map[string]error
+}
+
+func (e *GraphError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	msgs := make([]string, len(names))
+	for i, name := range names {
+		msgs[i] = fmt.Sprintf("node %q: %v", name, e.Errors[name])
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors of the failed nodes
+func (e *GraphError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
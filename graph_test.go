@@ -0,0 +1,110 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraph(t *testing.T) {
+	t.Run("Run", func(t *testing.T) {
+		ctx := context.Background()
+		g := NewGraph()
+		require.NoError(t, AddNode(g, "user", nil, func(ctx context.Context, in *GraphResults) (string, error) {
+			return "alice", nil
+		}))
+		require.NoError(t, AddNode(g, "orders", []string{"user"}, func(ctx context.Context, in *GraphResults) (int, error) {
+			user, err := GraphValue[string](in, "user")
+			if err != nil {
+				return 0, err
+			}
+			return len(user), nil
+		}))
+		require.NoError(t, AddNode(g, "summary", []string{"user", "orders"}, func(ctx context.Context, in *GraphResults) (string, error) {
+			user, _ := GraphValue[string](in, "user")
+			orders, _ := GraphValue[int](in, "orders")
+			return strings.Repeat(user, orders), nil
+		}))
+
+		results, err := g.Run(ctx).Await(ctx)
+		require.NoError(t, err)
+		summary, err := GraphValue[string](results, "summary")
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat("alice", 5), summary)
+
+		var dot strings.Builder
+		require.NoError(t, results.WriteDOT(&dot))
+		require.Contains(t, dot.String(), `"user" -> "orders";`)
+		require.Contains(t, dot.String(), `"orders" -> "summary";`)
+	})
+
+	t.Run("SkipsDownstream", func(t *testing.T) {
+		ctx := context.Background()
+		ran := false
+		g := NewGraph()
+		require.NoError(t, g.Add("a", nil, func(ctx context.Context, in *GraphResults) (any, error) {
+			return nil, errors.New("boom")
+		}))
+		require.NoError(t, g.Add("b", []string{"a"}, func(ctx context.Context, in *GraphResults) (any, error) {
+			ran = true
+			return nil, nil
+		}))
+
+		_, err := g.Run(ctx).Await(ctx)
+		var graphErr *GraphError
+		require.ErrorAs(t, err, &graphErr)
+		require.EqualError(t, err, `node "a": boom`)
+		require.False(t, ran)
+
+		b, ok := graphErr.Results.Node("b")
+		require.True(t, ok)
+		require.True(t, b.Skipped)
+		require.ErrorIs(t, b.Err, ErrNodeSkipped)
+	})
+
+	t.Run("CanceledRejects", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		started := make(chan struct{})
+		release := make(chan struct{})
+		g := NewGraph()
+		require.NoError(t, g.Add("a", nil, func(ctx context.Context, in *GraphResults) (any, error) {
+			close(started)
+			<-release
+			return 1, nil
+		}))
+		require.NoError(t, g.Add("b", []string{"a"}, func(ctx context.Context, in *GraphResults) (any, error) {
+			return 2, nil
+		}))
+
+		p := g.Run(ctx)
+		<-started
+		cancel()
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+
+		_, err := p.Await(context.Background())
+		var graphErr *GraphError
+		require.ErrorAs(t, err, &graphErr)
+		require.ErrorIs(t, err, context.Canceled)
+		b, ok := graphErr.Results.Node("b")
+		require.True(t, ok)
+		require.True(t, b.Skipped)
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		ctx := context.Background()
+		noop := func(ctx context.Context, in *GraphResults) (any, error) { return nil, nil }
+		g := NewGraph()
+		require.NoError(t, g.Add("a", []string{"c"}, noop))
+		require.NoError(t, g.Add("b", []string{"a"}, noop))
+		require.NoError(t, g.Add("c", []string{"b"}, noop))
+		require.Error(t, g.Add("a", nil, noop))
+
+		_, err := g.Run(ctx).Await(ctx)
+		require.ErrorIs(t, err, ErrGraphCycle)
+	})
+}
@@ -0,0 +1,83 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Hedge calls factory and, each time delay passes without a result, calls it again up to maxHedges more times.
+// It resolves with the first successful attempt and cancels the context of the others.
+// A failed attempt starts the next hedge right away. If every attempt fails it rejects with all their errors joined.
+func Hedge[T any](ctx context.Context, factory func(ctx context.Context) *Promise[T], delay time.Duration, maxHedges int) *Promise[T] {
+	if factory == nil {
+		panic("factory must not be nil")
+	}
+	if maxHedges < 0 {
+		maxHedges = 0
+	}
+
+	return NewWithPool(func(resolve func(T), reject func(error)) {
+		hedgeCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		type outcome struct {
+			value T
+			err   error
+		}
+		outcomes := make(chan outcome, maxHedges+1)
+		launched, pending := 0, 0
+		launch := func() {
+			p := factory(hedgeCtx)
+			// The attempts that lose are abandoned on purpose
+			p.markHandled()
+			if launched > 0 {
+				incrementHedges()
+			}
+			launched++
+			pending++
+			defaultPool.Go(func() {
+				value, err := p.Await(hedgeCtx)
+				outcomes <- outcome{value: value, err: err}
+			})
+		}
+
+		launch()
+		timer := ClockFromContext(ctx).NewTimer(delay)
+		defer timer.Stop()
+
+		var errs []error
+		for {
+			select {
+			case <-ctx.Done():
+				reject(ctx.Err())
+				return
+			case <-timer.C():
+				if launched <= maxHedges {
+					launch()
+					timer.Reset(delay)
+				}
+			case o := <-outcomes:
+				pending--
+				if o.err == nil {
+					resolve(o.value)
+					return
+				}
+				errs = append(errs, o.err)
+				if launched <= maxHedges {
+					launch()
+					if !timer.Stop() {
+						select {
+						case <-timer.C():
+						default:
+						}
+					}
+					timer.Reset(delay)
+				} else if pending == 0 {
+					reject(errors.Join(errs...))
+					return
+				}
+			}
+		}
+	}, defaultPool)
+}
@@ -0,0 +1,52 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHedge(t *testing.T) {
+	t.Run("HedgeWinsOverSlowAttempt", func(t *testing.T) {
+		ctx := context.Background()
+		var attempts atomic.Int32
+		canceled := make(chan struct{})
+		p := Hedge(ctx, func(ctx context.Context) *Promise[int] {
+			n := attempts.Add(1)
+			return New(func(resolve func(int), reject func(error)) {
+				if n == 1 {
+					<-ctx.Done()
+					close(canceled)
+					reject(ctx.Err())
+					return
+				}
+				resolve(int(n))
+			})
+		}, 20*time.Millisecond, 2)
+
+		result, err := p.Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, result)
+		<-canceled
+		require.Equal(t, int32(2), attempts.Load())
+	})
+
+	t.Run("AllAttemptsFail", func(t *testing.T) {
+		ctx := context.Background()
+		var attempts atomic.Int32
+		p := Hedge(ctx, func(ctx context.Context) *Promise[int] {
+			attempts.Add(1)
+			return AsyncTask(func() (int, error) {
+				return 0, errors.New("boom")
+			})
+		}, time.Hour, 2)
+
+		_, err := p.Await(ctx)
+		require.ErrorContains(t, err, "boom")
+		require.Equal(t, int32(3), attempts.Load())
+	})
+}
@@ -0,0 +1,28 @@
+package promise4g
+
+import "sync/atomic"
+
+// peakTracker records the highest total weight of tasks running at the same time
+type peakTracker struct {
+	running atomic.Int64
+	peak    atomic.Int64
+}
+
+// trackPeak counts weight as running until the returned function is called
+func (pt *peakTracker) trackPeak(weight int64) (done func()) {
+	now := pt.running.Add(weight)
+	for {
+		old := pt.peak.Load()
+		if now <= old || pt.peak.CompareAndSwap(old, now) {
+			break
+		}
+	}
+	return func() {
+		pt.running.Add(-weight)
+	}
+}
+
+// max returns the highest total weight seen so far
+func (pt *peakTracker) max() int64 {
+	return pt.peak.Load()
+}
@@ -0,0 +1,49 @@
+package promise4g
+
+import (
+	"context"
+	"iter"
+)
+
+// AsCompleted returns an iterator over the outcomes of the promises in the order they settle.
+// Each outcome is yielded with the index of its promise. Promises still pending when ctx is done
+// are yielded with the context error. Stopping the iteration early stops waiting for the rest.
+func AsCompleted[T any](ctx context.Context, promises ...*Promise[T]) iter.Seq2[int, Result[T]] {
+	return func(yield func(int, Result[T]) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		type completed struct {
+			index  int
+			result Result[T]
+		}
+		ch := make(chan completed, len(promises))
+		for i, p := range promises {
+			// Their outcome is passed on to yield, even if the loop stops early
+			p.markHandled()
+			defaultPool.Go(func() {
+				value, err := p.Await(ctx)
+				ch <- completed{index: i, result: Result[T]{Value: value, Err: err}}
+			})
+		}
+
+		for range promises {
+			c := <-ch
+			if !yield(c.index, c.result) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the values of the promises in the order they resolve.
+// The iteration stops at the first rejection; use AsCompleted to observe errors.
+func Values[T any](ctx context.Context, promises ...*Promise[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, result := range AsCompleted(ctx, promises...) {
+			if result.Err != nil || !yield(result.Value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsCompleted(t *testing.T) {
+	ctx := context.Background()
+	p1 := New(func(resolve func(string), reject func(error)) {
+		time.Sleep(60 * time.Millisecond)
+		resolve("slow")
+	})
+	p2 := New(func(resolve func(string), reject func(error)) {
+		resolve("fast")
+	})
+	p3 := New(func(resolve func(string), reject func(error)) {
+		time.Sleep(30 * time.Millisecond)
+		reject(errors.New("error"))
+	})
+
+	var indexes []int
+	var values []string
+	for i, result := range AsCompleted(ctx, p1, p2, p3) {
+		indexes = append(indexes, i)
+		if result.Err == nil {
+			values = append(values, result.Value)
+		}
+	}
+	require.Equal(t, []int{1, 2, 0}, indexes)
+	require.Equal(t, []string{"fast", "slow"}, values)
+}
+
+func TestValues(t *testing.T) {
+	ctx := context.Background()
+	p1 := New(func(resolve func(int), reject func(error)) {
+		resolve(1)
+	})
+	p2 := New(func(resolve func(int), reject func(error)) {
+		time.Sleep(30 * time.Millisecond)
+		reject(errors.New("error"))
+	})
+	p3 := New(func(resolve func(int), reject func(error)) {
+		time.Sleep(60 * time.Millisecond)
+		resolve(3)
+	})
+
+	var values []int
+	for v := range Values(ctx, p1, p2, p3) {
+		values = append(values, v)
+	}
+	require.Equal(t, []int{1}, values)
+}
@@ -0,0 +1,164 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrPoolClosed is returned for tasks submitted to a pool that has been shut down
+var ErrPoolClosed = errors.New("pool is closed")
+
+// Lifecycle is implemented by pools that can be stopped gracefully, such as a ManagedPool
+// or the pools returned by FromAntsPool and FromConcPool
+type Lifecycle interface {
+	// Shutdown stops accepting new tasks and waits for the running ones until ctx is done.
+	// It returns the context error if tasks were still running when it gave up.
+	// It does not reject the promises of those tasks, they stay pending until their tasks settle them.
+	Shutdown(ctx context.Context) error
+	// Drain waits until no tasks are running, or until ctx is done, without closing the pool
+	Drain(ctx context.Context) error
+	// Closed reports whether Shutdown has been called
+	Closed() bool
+}
+
+var _ Lifecycle = (*ManagedPool)(nil)
+
+// ManagedPool is a Pool that keeps track of its running tasks so that it can be shut down gracefully.
+// After Shutdown, promises submitted to it reject with ErrPoolClosed and Go panics with ErrPoolClosed.
+type ManagedPool struct {
+	pool    Pool
+	release func()
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	mu          sync.Mutex
+	closed      bool
+	running     int
+	idle        chan struct{} // closed once running drops back to zero
+	releaseOnce sync.Once
+}
+
+// NewManagedPool creates a ManagedPool that runs tasks on pool.
+// If pool is nil, every task runs on its own goroutine like with the default pool.
+func NewManagedPool(pool Pool) *ManagedPool {
+	if pool == nil {
+		pool = newDefaultPool()
+	}
+	return newManagedPool(pool, nil)
+}
+
+// newManagedPool creates a ManagedPool that calls release once it has been shut down
+func newManagedPool(pool Pool, release func()) *ManagedPool {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ManagedPool{
+		pool:    pool,
+		release: release,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Go runs f on the pool. It panics with ErrPoolClosed if the pool has been shut down.
+// Promises are counted in by submit directly, without going through Go.
+func (m *ManagedPool) Go(f func()) {
+	if !m.acquire() {
+		panic(ErrPoolClosed)
+	}
+	submitted := false
+	defer func() {
+		// The inner pool panicked instead of taking f
+		if !submitted {
+			m.done()
+		}
+	}()
+	m.pool.Go(func() {
+		defer m.done()
+		f()
+	})
+	submitted = true
+}
+
+func (m *ManagedPool) observeExecution(d time.Duration, err error) {
+	if observer, ok := m.pool.(executionObserver); ok {
+		observer.observeExecution(d, err)
+	}
+}
+
+// Context returns a context that is canceled once Shutdown returns.
+// Tasks that can stop early watch it to give up the work Shutdown did not wait for.
+func (m *ManagedPool) Context() context.Context {
+	return m.ctx
+}
+
+// Shutdown stops accepting new tasks and waits for the running ones until ctx is done,
+// then cancels the context returned by Context. It returns the context error if tasks were still running.
+// Their promises are not rejected: they stay pending until the tasks settle them, which tasks that
+// watch Context do right away.
+func (m *ManagedPool) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	m.closed = true
+	m.mu.Unlock()
+
+	err := m.Drain(ctx)
+	m.cancel()
+	m.releaseOnce.Do(func() {
+		if m.release != nil {
+			m.release()
+		}
+	})
+	return err
+}
+
+// Drain waits until no tasks are running, or until ctx is done, without closing the pool
+func (m *ManagedPool) Drain(ctx context.Context) error {
+	m.mu.Lock()
+	if m.running == 0 {
+		m.mu.Unlock()
+		return nil
+	}
+	idle := m.idle
+	m.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Closed reports whether Shutdown has been called
+func (m *ManagedPool) Closed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+func (m *ManagedPool) String() string {
+	return poolName(m.pool)
+}
+
+// acquire counts a new task in, unless the pool is closed
+func (m *ManagedPool) acquire() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return false
+	}
+	if m.running == 0 {
+		m.idle = make(chan struct{})
+	}
+	m.running++
+	return true
+}
+
+func (m *ManagedPool) done() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.running--
+	if m.running == 0 {
+		close(m.idle)
+	}
+}
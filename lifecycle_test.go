@@ -0,0 +1,149 @@
+package promise4g
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/panjf2000/ants/v2"
+	conc "github.com/sourcegraph/conc/pool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagedPool(t *testing.T) {
+	t.Run("ShutdownWaitsForRunningTasks", func(t *testing.T) {
+		ctx := context.Background()
+		pool := NewManagedPool(nil)
+		release := make(chan struct{})
+		p := NewWithPool(func(resolve func(int), reject func(error)) {
+			<-release
+			resolve(1)
+		}, pool)
+
+		shutdown := make(chan error, 1)
+		go func() {
+			shutdown <- pool.Shutdown(ctx)
+		}()
+		require.Eventually(t, pool.Closed, time.Second, time.Millisecond)
+		select {
+		case <-shutdown:
+			t.Fatal("shutdown returned before the running task finished")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		close(release)
+		require.NoError(t, <-shutdown)
+		result, err := p.Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, result)
+	})
+
+	t.Run("RejectsAfterShutdown", func(t *testing.T) {
+		pool := NewManagedPool(nil)
+		require.NoError(t, pool.Shutdown(context.Background()))
+		require.True(t, pool.Closed())
+
+		_, err := NewWithPool(func(resolve func(int), reject func(error)) {
+			resolve(1)
+		}, pool).Await(context.Background())
+		require.ErrorIs(t, err, ErrPoolClosed)
+		require.PanicsWithValue(t, ErrPoolClosed, func() {
+			pool.Go(func() {})
+		})
+	})
+
+	t.Run("ShutdownDeadline", func(t *testing.T) {
+		pool := NewManagedPool(nil)
+		release := make(chan struct{})
+		defer close(release)
+		canceled := make(chan struct{})
+		NewWithPool(func(resolve func(int), reject func(error)) {
+			select {
+			case <-release:
+			case <-pool.Context().Done():
+				close(canceled)
+			}
+			resolve(1)
+		}, pool)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, pool.Shutdown(ctx), context.DeadlineExceeded)
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Fatal("task was not told to stop")
+		}
+	})
+
+	t.Run("DrainKeepsPoolOpen", func(t *testing.T) {
+		ctx := context.Background()
+		pool := NewManagedPool(nil)
+		done := make(chan struct{})
+		pool.Go(func() {
+			time.Sleep(10 * time.Millisecond)
+			close(done)
+		})
+		require.NoError(t, pool.Drain(ctx))
+		select {
+		case <-done:
+		default:
+			t.Fatal("drain returned before the task finished")
+		}
+		require.False(t, pool.Closed())
+
+		result, err := NewWithPool(func(resolve func(int), reject func(error)) {
+			resolve(2)
+		}, pool).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, result)
+	})
+
+	t.Run("InnerPoolPanics", func(t *testing.T) {
+		pool := NewManagedPool(wrapFunc(func(func()) {
+			panic("full")
+		}))
+		require.PanicsWithValue(t, "full", func() {
+			pool.Go(func() {})
+		})
+		require.PanicsWithValue(t, "full", func() {
+			NewWithPool(func(resolve func(int), reject func(error)) {
+				resolve(1)
+			}, pool)
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		require.NoError(t, pool.Drain(ctx))
+		require.NoError(t, pool.Shutdown(ctx))
+	})
+
+	t.Run("Adapters", func(t *testing.T) {
+		antsPool, err := ants.NewPool(2)
+		require.NoError(t, err)
+		pools := map[string]Pool{
+			"ants": FromAntsPool(antsPool),
+			"conc": FromConcPool(conc.New()),
+		}
+		for name, pool := range pools {
+			t.Run(name, func(t *testing.T) {
+				ctx := context.Background()
+				lifecycle, ok := pool.(Lifecycle)
+				require.True(t, ok)
+
+				result, err := NewWithPool(func(resolve func(int), reject func(error)) {
+					resolve(1)
+				}, pool).Await(ctx)
+				require.NoError(t, err)
+				require.Equal(t, 1, result)
+
+				require.NoError(t, lifecycle.Shutdown(ctx))
+				_, err = NewWithPool(func(resolve func(int), reject func(error)) {
+					resolve(1)
+				}, pool).Await(ctx)
+				require.ErrorIs(t, err, ErrPoolClosed)
+			})
+		}
+		require.True(t, antsPool.IsClosed())
+	})
+}
@@ -0,0 +1,164 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type loggerHolder struct {
+	*slog.Logger
+}
+
+var (
+	currentLogger atomic.Value // loggerHolder
+	slowThreshold atomic.Int64
+)
+
+func init() {
+	currentLogger.Store(loggerHolder{})
+}
+
+// SetLogger sets the default logger of the library. Promises with a logger report recovered panics,
+// rejections that nobody awaited before the Promise was garbage collected or its context was done,
+// and slow promises.
+// A nil logger, the default, turns logging off.
+func SetLogger(l *slog.Logger) {
+	currentLogger.Store(loggerHolder{l})
+}
+
+func getLogger() *slog.Logger {
+	return currentLogger.Load().(loggerHolder).Logger
+}
+
+// SetSlowThreshold sets the default execution time above which a Promise is logged as slow.
+// Zero, the default, turns it off.
+func SetSlowThreshold(d time.Duration) {
+	slowThreshold.Store(int64(d))
+}
+
+type loggerKey struct{}
+
+// ContextWithLogger returns a copy of ctx carrying the given logger.
+// Promises created WithContext(ctx) use it instead of the default logger.
+func ContextWithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// LoggerFromContext returns the logger carried by ctx, or the default logger, which may be nil
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok && l != nil {
+		return l
+	}
+	return getLogger()
+}
+
+// promiseLog is the logging state of a Promise. It is kept apart from the Promise so that
+// a finalizer can tell when the Promise is gone without keeping it alive.
+type promiseLog struct {
+	logger  *slog.Logger
+	name    string
+	pool    string
+	slow    time.Duration
+	scope   context.Context
+	handled atomic.Bool
+
+	mu        sync.Mutex
+	rejection *rejectionLog
+	stop      func() bool // stops the report at the end of scope
+}
+
+// rejectionLog reports a rejection that nobody has awaited. It does not point back to its promiseLog,
+// so that waiting for the end of scope does not keep the promiseLog from being finalized.
+type rejectionLog struct {
+	logger *slog.Logger
+	attrs  []any
+	done   atomic.Bool // set once the rejection has been reported or handled
+}
+
+func (r *rejectionLog) report() {
+	if r.done.CompareAndSwap(false, true) {
+		r.logger.Error("unhandled promise rejection", r.attrs...)
+	}
+}
+
+// newPromiseLog returns the logging state of a Promise, or nil if it has no logger
+func newPromiseLog(o *options) *promiseLog {
+	if o.logger == nil {
+		return nil
+	}
+	l := &promiseLog{
+		logger: o.logger,
+		name:   o.name,
+		slow:   o.slowThreshold,
+		scope:  o.ctx,
+	}
+	if o.pool != nil {
+		l.pool = poolName(o.pool)
+	}
+	return l
+}
+
+func (l *promiseLog) attrs(d time.Duration) []any {
+	return []any{
+		slog.String("promise", l.name),
+		slog.Duration("duration", d),
+		slog.String("pool", l.pool),
+	}
+}
+
+// handle records that the outcome of the Promise has been seen, or handed to something that takes care of it
+func (l *promiseLog) handle() {
+	l.handled.Store(true)
+	l.mu.Lock()
+	r, stop := l.rejection, l.stop
+	l.stop = nil
+	l.mu.Unlock()
+	if r != nil {
+		r.done.Store(true)
+	}
+	if stop != nil {
+		stop()
+	}
+}
+
+// settled logs a slow Promise, and arranges for a rejection to be logged if nobody awaits it before
+// the Promise is garbage collected or the context it was given with WithContext is done.
+// Cancellations are not logged, they are how abandoned promises usually end.
+func (l *promiseLog) settled(d time.Duration, err error) {
+	if l.slow > 0 && d > l.slow {
+		l.logger.Warn("slow promise", l.attrs(d)...)
+	}
+	if err == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	r := &rejectionLog{logger: l.logger, attrs: append(l.attrs(d), slog.Any("error", err))}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.handled.Load() {
+		return
+	}
+	l.rejection = r
+	// A scope that is already over when the Promise settles is left to the finalizer,
+	// so that the caller still gets the chance to await it
+	if l.scope.Err() == nil {
+		l.stop = context.AfterFunc(l.scope, r.report)
+	}
+	runtime.SetFinalizer(l, func(l *promiseLog) {
+		l.rejection.report()
+	})
+}
+
+// panicked logs a panic recovered from the task of the Promise, with the stack it was raised from
+func (l *promiseLog) panicked(d time.Duration, r any) {
+	l.logger.Error("promise task panicked", append(l.attrs(d),
+		slog.Any("panic", r),
+		slog.String("stack", string(debug.Stack())),
+	)...)
+}
@@ -0,0 +1,200 @@
+package promise4g
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// logBuffer collects log output that is written and read from different goroutines
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *logBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func newTestLogger() (*slog.Logger, *logBuffer) {
+	buf := &logBuffer{}
+	return slog.New(slog.NewTextHandler(buf, nil)), buf
+}
+
+func TestLogging(t *testing.T) {
+	t.Run("UnhandledRejection", func(t *testing.T) {
+		logger, buf := newTestLogger()
+		done := make(chan struct{})
+		func() {
+			NewWithOptions(func(resolve func(int), reject func(error)) {
+				defer close(done)
+				reject(errors.New("lost"))
+			}, WithLogger(logger), WithName("fire-and-forget"))
+		}()
+		<-done
+
+		require.Eventually(t, func() bool {
+			runtime.GC()
+			return strings.Contains(buf.String(), "unhandled promise rejection")
+		}, 2*time.Second, 10*time.Millisecond)
+		require.Contains(t, buf.String(), "promise=fire-and-forget")
+		require.Contains(t, buf.String(), "pool=default")
+		require.Contains(t, buf.String(), "error=lost")
+	})
+
+	t.Run("AwaitedRejection", func(t *testing.T) {
+		logger, buf := newTestLogger()
+		_, err := NewWithOptions(func(resolve func(int), reject func(error)) {
+			reject(errors.New("seen"))
+		}, WithLogger(logger)).Await(context.Background())
+		require.Error(t, err)
+
+		for range 3 {
+			runtime.GC()
+			time.Sleep(10 * time.Millisecond)
+		}
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("ScopeEnds", func(t *testing.T) {
+		logger, buf := newTestLogger()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		p := NewWithOptions(func(resolve func(int), reject func(error)) {
+			reject(errors.New("dropped"))
+		}, WithLogger(logger), WithName("scoped"), WithContext(ctx))
+		<-p.done
+		require.Empty(t, buf.String())
+
+		cancel()
+		require.Eventually(t, func() bool {
+			return strings.Contains(buf.String(), "unhandled promise rejection")
+		}, time.Second, time.Millisecond)
+		require.Contains(t, buf.String(), "promise=scoped")
+		require.Equal(t, 1, strings.Count(buf.String(), "unhandled promise rejection"))
+		runtime.KeepAlive(p)
+	})
+
+	t.Run("AwaitedBeforeScopeEnds", func(t *testing.T) {
+		logger, buf := newTestLogger()
+		ctx, cancel := context.WithCancel(context.Background())
+		p := NewWithOptions(func(resolve func(int), reject func(error)) {
+			reject(errors.New("seen"))
+		}, WithLogger(logger), WithContext(ctx))
+		<-p.done
+		_, err := p.Await(context.Background())
+		require.Error(t, err)
+
+		cancel()
+		time.Sleep(10 * time.Millisecond)
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("Abandoned", func(t *testing.T) {
+		ctx := context.Background()
+		logger, buf := newTestLogger()
+		late := make(chan struct{})
+		leftovers := make(chan struct{}, 2)
+		loser := func() *Promise[int] {
+			return NewWithOptions(func(resolve func(int), reject func(error)) {
+				defer func() {
+					leftovers <- struct{}{}
+				}()
+				<-late
+				reject(errors.New("too late"))
+			}, WithLogger(logger))
+		}
+		winner := func() *Promise[int] {
+			return NewWithOptions(func(resolve func(int), reject func(error)) {
+				resolve(1)
+			}, WithLogger(logger))
+		}
+
+		_, err := Some(ctx, 1, winner(), loser()).Await(ctx)
+		require.NoError(t, err)
+		var attempts atomic.Int32
+		_, err = Hedge(ctx, func(context.Context) *Promise[int] {
+			if attempts.Add(1) == 1 {
+				return loser()
+			}
+			return winner()
+		}, time.Millisecond, 1).Await(ctx)
+		require.NoError(t, err)
+		close(late)
+		<-leftovers
+		<-leftovers
+
+		for range 3 {
+			runtime.GC()
+			time.Sleep(10 * time.Millisecond)
+		}
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		logger, buf := newTestLogger()
+		_, err := NewWithOptions(func(resolve func(int), reject func(error)) {
+			panic("boom")
+		}, WithLogger(logger), WithName("panicky")).Await(context.Background())
+		require.EqualError(t, err, "boom")
+		require.Contains(t, buf.String(), "promise task panicked")
+		require.Contains(t, buf.String(), "promise=panicky")
+		require.Contains(t, buf.String(), "panic=boom")
+		require.Contains(t, buf.String(), "log_test.go")
+	})
+
+	t.Run("Slow", func(t *testing.T) {
+		logger, buf := newTestLogger()
+		_, err := NewWithOptions(func(resolve func(int), reject func(error)) {
+			time.Sleep(20 * time.Millisecond)
+			resolve(1)
+		}, WithLogger(logger), WithName("sluggish"), WithSlowThreshold(5*time.Millisecond)).Await(context.Background())
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "slow promise")
+		require.Contains(t, buf.String(), "promise=sluggish")
+
+		_, err = NewWithOptions(func(resolve func(int), reject func(error)) {
+			resolve(1)
+		}, WithLogger(logger), WithName("quick"), WithSlowThreshold(time.Second)).Await(context.Background())
+		require.NoError(t, err)
+		require.NotContains(t, buf.String(), "promise=quick")
+	})
+
+	t.Run("ContextLogger", func(t *testing.T) {
+		logger, buf := newTestLogger()
+		ctx := ContextWithLogger(context.Background(), logger)
+		_, err := NewWithOptions(func(resolve func(int), reject func(error)) {
+			panic("from context")
+		}, WithContext(ctx)).Await(context.Background())
+		require.Error(t, err)
+		require.Contains(t, buf.String(), "panic=\"from context\"")
+	})
+
+	t.Run("DefaultLogger", func(t *testing.T) {
+		logger, buf := newTestLogger()
+		SetLogger(logger)
+		defer SetLogger(nil)
+		_, err := New(func(resolve func(int), reject func(error)) {
+			panic("global")
+		}).Await(context.Background())
+		require.Error(t, err)
+		require.Contains(t, buf.String(), "panic=global")
+	})
+}
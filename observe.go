@@ -0,0 +1,62 @@
+package promise4g
+
+import "context"
+
+// OnSettle registers fn to be called with the outcome of the Promise once it settles.
+// fn runs exactly once on the pool of the Promise, or right away on the calling goroutine if the Promise
+// has already settled. It runs on the default pool instead if the pool is closed, refuses fn, admits tasks
+// itself (such as WeightedPool, Bulkhead and RateLimitPool) or is an AdaptivePool.
+// Unlike Then and Finally no new Promise is created.
+// It returns a function that cancels the registration if fn has not been scheduled yet.
+func (p *Promise[T]) OnSettle(fn func(T, error)) (unsubscribe func()) {
+	if fn == nil {
+		panic("fn must not be nil")
+	}
+	callback := func() {
+		fn(p.Await(context.Background()))
+	}
+
+	p.callbacksMu.Lock()
+	if p.callbacksCalled {
+		p.callbacksMu.Unlock()
+		callback()
+		return func() {}
+	}
+	if p.callbacks == nil {
+		p.callbacks = make(map[uint64]func())
+	}
+	id := p.nextCallbackID
+	p.nextCallbackID++
+	p.callbacks[id] = callback
+	p.callbacksMu.Unlock()
+
+	return func() {
+		p.callbacksMu.Lock()
+		defer p.callbacksMu.Unlock()
+		delete(p.callbacks, id)
+	}
+}
+
+// OnResolve registers fn to be called with the value of the Promise if it resolves. See OnSettle.
+func (p *Promise[T]) OnResolve(fn func(T)) (unsubscribe func()) {
+	if fn == nil {
+		panic("fn must not be nil")
+	}
+	return p.OnSettle(func(value T, err error) {
+		if err == nil {
+			fn(value)
+		}
+	})
+}
+
+// OnReject registers fn to be called with the error of the Promise if it rejects. See OnSettle.
+func (p *Promise[T]) OnReject(fn func(error)) (unsubscribe func()) {
+	if fn == nil {
+		panic("fn must not be nil")
+	}
+	return p.OnSettle(func(_ T, err error) {
+		if err != nil {
+			fn(err)
+		}
+	})
+}
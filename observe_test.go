@@ -0,0 +1,166 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/panjf2000/ants/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromise_OnSettle(t *testing.T) {
+	t.Run("Resolve", func(t *testing.T) {
+		release := make(chan struct{})
+		p := New(func(resolve func(int), reject func(error)) {
+			<-release
+			resolve(1)
+		})
+
+		settled := make(chan Result[int], 1)
+		resolved := make(chan int, 1)
+		rejected := make(chan error, 1)
+		p.OnSettle(func(v int, err error) {
+			settled <- Result[int]{Value: v, Err: err}
+		})
+		p.OnResolve(func(v int) {
+			resolved <- v
+		})
+		p.OnReject(func(err error) {
+			rejected <- err
+		})
+		close(release)
+
+		require.Equal(t, Result[int]{Value: 1}, <-settled)
+		require.Equal(t, 1, <-resolved)
+		require.Empty(t, rejected)
+	})
+
+	t.Run("RunsOnPromisePool", func(t *testing.T) {
+		var submitted atomic.Int32
+		pool := wrapFunc(func(f func()) {
+			submitted.Add(1)
+			go f()
+		})
+		release := make(chan struct{})
+		p := NewWithPool(func(resolve func(int), reject func(error)) {
+			<-release
+			resolve(1)
+		}, pool)
+
+		done := make(chan struct{})
+		p.OnSettle(func(int, error) {
+			close(done)
+		})
+		close(release)
+		<-done
+		require.Equal(t, int32(2), submitted.Load())
+	})
+
+	t.Run("BoundedPool", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		antsPool, err := ants.NewPool(1)
+		require.NoError(t, err)
+		defer antsPool.Release()
+		pool := FromAntsPool(antsPool)
+
+		release := make(chan struct{})
+		p := NewWithPool(func(resolve func(int), reject func(error)) {
+			<-release
+			resolve(1)
+		}, pool)
+		done := make(chan struct{})
+		p.OnSettle(func(int, error) {
+			close(done)
+		})
+		close(release)
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			t.Fatal("callback did not run")
+		}
+		result, err := NewWithPool(func(resolve func(int), reject func(error)) {
+			resolve(2)
+		}, pool).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, result)
+	})
+
+	t.Run("SkipsAdmittingPool", func(t *testing.T) {
+		ctx := context.Background()
+		limiter := NewTokenBucket(0.001, 2)
+		pool := RateLimitPool("callbacks", defaultPool, limiter)
+
+		done := make(chan struct{})
+		NewWithPool(func(resolve func(int), reject func(error)) {
+			resolve(1)
+		}, pool).OnSettle(func(int, error) {
+			close(done)
+		})
+		<-done
+
+		result, err := NewWithPool(func(resolve func(int), reject func(error)) {
+			resolve(2)
+		}, pool).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, result)
+		require.False(t, limiter.Allow())
+	})
+
+	t.Run("ClosedPoolFallsBack", func(t *testing.T) {
+		pool := NewManagedPool(nil)
+		release := make(chan struct{})
+		p := NewWithPool(func(resolve func(int), reject func(error)) {
+			<-release
+			resolve(1)
+		}, pool)
+
+		done := make(chan struct{})
+		p.OnSettle(func(int, error) {
+			close(done)
+		})
+		shutdown := make(chan error, 1)
+		go func() {
+			shutdown <- pool.Shutdown(context.Background())
+		}()
+		require.Eventually(t, pool.Closed, time.Second, time.Millisecond)
+		close(release)
+		<-done
+		require.NoError(t, <-shutdown)
+	})
+
+	t.Run("AlreadySettled", func(t *testing.T) {
+		p := rejected[int](errors.New("boom"))
+		var got error
+		p.OnReject(func(err error) {
+			got = err
+		})
+		require.EqualError(t, got, "boom")
+	})
+
+	t.Run("Unsubscribe", func(t *testing.T) {
+		release := make(chan struct{})
+		p := New(func(resolve func(int), reject func(error)) {
+			<-release
+			resolve(1)
+		})
+
+		called := make(chan struct{}, 1)
+		unsubscribe := p.OnSettle(func(int, error) {
+			called <- struct{}{}
+		})
+		unsubscribe()
+		done := make(chan struct{})
+		p.OnSettle(func(int, error) {
+			close(done)
+		})
+		close(release)
+
+		<-done
+		require.Empty(t, called)
+	})
+}
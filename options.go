@@ -0,0 +1,116 @@
+package promise4g
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Option configures a Promise created with NewWithOptions
+type Option func(*options)
+
+type options struct {
+	pool   Pool
+	name   string
+	clock  Clock
+	ctx    context.Context
+	weight int64
+	labels []string
+
+	logger        *slog.Logger
+	slowThreshold time.Duration
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		pool:   defaultPool,
+		ctx:    context.Background(),
+		weight: 1,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.clock == nil {
+		o.clock = ClockFromContext(o.ctx)
+	}
+	if o.logger == nil {
+		o.logger = LoggerFromContext(o.ctx)
+	}
+	if o.slowThreshold == 0 {
+		o.slowThreshold = time.Duration(slowThreshold.Load())
+	}
+	return o
+}
+
+// WithPool runs the task of the Promise on the given pool
+func WithPool(pool Pool) Option {
+	return func(o *options) {
+		o.pool = pool
+	}
+}
+
+// WithName gives the Promise a name that shows up in debugging output,
+// and as the "promise" pprof label of its task in CPU and goroutine profiles
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// WithClock makes the Promise measure its execution time, and Timeout wait for it, using the given clock.
+// Without it the Promise uses the clock of the context given WithContext, or the default clock.
+func WithClock(c Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// WithContext bounds how long the Promise waits to be admitted by its pool, such as a WeightedPool.
+// If ctx is done first the Promise rejects with the context error and its task never runs.
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// WithWeight declares the cost of the task, such as its estimated memory, for pools that admit tasks by weight.
+// Other pools ignore it. The default weight is 1.
+func WithWeight(weight int64) Option {
+	return func(o *options) {
+		o.weight = weight
+	}
+}
+
+// WithLabels sets pprof labels, given as key-value pairs, that the task of the Promise runs with.
+// Profiles can then be split by them. Labels of the context given WithContext are kept.
+func WithLabels(kv ...string) Option {
+	if len(kv)%2 != 0 {
+		panic("labels must be key-value pairs")
+	}
+	return func(o *options) {
+		o.labels = append(o.labels, kv...)
+	}
+}
+
+// WithLogger makes the Promise log to l instead of the logger of its context or the default one
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithSlowThreshold logs the Promise as slow if it takes longer than d to settle.
+// A negative d turns it off for this Promise regardless of SetSlowThreshold.
+func WithSlowThreshold(d time.Duration) Option {
+	return func(o *options) {
+		o.slowThreshold = d
+	}
+}
+
+// pprofLabels returns the pprof labels of the task, including its name
+func (o *options) pprofLabels() []string {
+	if o.name == "" {
+		return o.labels
+	}
+	return append([]string{"promise", o.name}, o.labels...)
+}
@@ -1,6 +1,10 @@
 package promise4g
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/panjf2000/ants/v2"
 	conc "github.com/sourcegraph/conc/pool"
 )
@@ -13,26 +17,64 @@ type Pool interface {
 	Go(f func())
 }
 
+// admitter is implemented by pools that decide when, and whether, a task may start.
+// admit eventually either starts run or calls fail, waiting no longer than ctx allows.
+type admitter interface {
+	admit(ctx context.Context, weight int64, run func(), fail func(error))
+}
+
+// executionObserver is implemented by pools that learn from the outcome of their promises.
+// observeExecution is called when a promise running on the pool settles, with the time since its task started.
+type executionObserver interface {
+	observeExecution(d time.Duration, err error)
+}
+
 type wrapFunc func(f func())
 
 func (wf wrapFunc) Go(f func()) {
 	wf(f)
 }
 
+// namedPool is a Pool with a name that shows up in debugging output
+type namedPool struct {
+	Pool
+	name string
+}
+
+func (np namedPool) String() string {
+	return np.name
+}
+
+// poolName returns a name for the pool suitable for debugging output
+func poolName(pool Pool) string {
+	if s, ok := pool.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", pool)
+}
+
 func newDefaultPool() Pool {
-	return wrapFunc(func(f func()) {
-		go f()
-	})
+	return namedPool{
+		Pool: wrapFunc(func(f func()) {
+			go f()
+		}),
+		name: "default",
+	}
 }
 
+// FromConcPool adapts a conc pool. The returned pool implements Lifecycle.
 func FromConcPool(p *conc.Pool) Pool {
-	return wrapFunc(p.Go)
+	return newManagedPool(namedPool{Pool: wrapFunc(p.Go), name: "conc"}, nil)
 }
 
+// FromAntsPool adapts an ants pool. The returned pool implements Lifecycle and releases p once shut down.
 func FromAntsPool(p *ants.Pool) Pool {
-	return wrapFunc(func(f func()) {
-		if err := p.Submit(f); err != nil {
-			panic(err)
-		}
-	})
+	return newManagedPool(namedPool{
+		Pool: wrapFunc(func(f func()) {
+			if err := p.Submit(f); err != nil {
+				panic(err)
+			}
+		}),
+		name: "ants",
+	}, p.Release)
 }
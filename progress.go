@@ -0,0 +1,173 @@
+package promise4g
+
+import "sync"
+
+// progressHub keeps the latest progress report of a task and hands it to subscribers.
+// Reports are coalesced, so a slow subscriber only sees the most recent one and never blocks the task.
+type progressHub struct {
+	mu      sync.Mutex
+	latest  any
+	version uint64
+	closed  bool
+	nextID  uint64
+	subs    map[uint64]chan struct{}
+}
+
+func newProgressHub() *progressHub {
+	return &progressHub{subs: make(map[uint64]chan struct{})}
+}
+
+func (h *progressHub) report(value any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.latest = value
+	h.version++
+	h.notify()
+}
+
+func (h *progressHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	h.notify()
+}
+
+// notify wakes every subscriber without blocking. h.mu must be held.
+func (h *progressHub) notify() {
+	for _, signal := range h.subs {
+		select {
+		case signal <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// subscribe calls fn with every report it gets to see, then done once the hub is closed or unsubscribed
+func (h *progressHub) subscribe(fn func(any), done func()) (unsubscribe func()) {
+	h.mu.Lock()
+	id := h.nextID
+	h.nextID++
+	signal := make(chan struct{}, 1)
+	h.subs[id] = signal
+	if h.version > 0 || h.closed {
+		signal <- struct{}{}
+	}
+	h.mu.Unlock()
+
+	stop := make(chan struct{})
+	var once sync.Once
+	defaultPool.Go(func() {
+		defer done()
+		var seen uint64
+		for {
+			select {
+			case <-stop:
+				return
+			case <-signal:
+			}
+
+			h.mu.Lock()
+			value, version, closed := h.latest, h.version, h.closed
+			h.mu.Unlock()
+			if version > seen {
+				seen = version
+				fn(value)
+			}
+			if closed {
+				return
+			}
+		}
+	})
+
+	return func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs, id)
+			h.mu.Unlock()
+			close(stop)
+		})
+	}
+}
+
+// NewWithProgress creates a new Promise whose task can report progress of type P while it runs.
+// Progress stops once the Promise settles or the task returns, and a Promise that its pool rejects
+// without running the task reports no progress. Promises chained with Then, Catch or Finally
+// expose the progress of the Promise they were chained to.
+func NewWithProgress[T, P any](task func(resolve func(T), reject func(error), report func(P)), opts ...Option) *Promise[T] {
+	if task == nil {
+		panic("task must not be nil")
+	}
+	o := newOptions(opts)
+	if o.pool == nil {
+		panic("pool must not be nil")
+	}
+	p := newPromise[T](o)
+	// The hub is closed when the Promise settles, even if the pool rejects it without running the task
+	p.progress = newProgressHub()
+	p.closeHub = true
+	p.submit(o.ctx, o.pool, o.weight, func(resolve func(T), reject func(error)) {
+		defer p.progress.close()
+		task(resolve, reject, func(progress P) {
+			p.progress.report(progress)
+		})
+	})
+	return p
+}
+
+// inheritProgress makes to expose the progress of from
+func inheritProgress[A, B any](from *Promise[A], to *Promise[B]) *Promise[B] {
+	to.progress = from.progress
+	return to
+}
+
+// OnProgress calls fn on its own goroutine with the progress reports of p.
+// Reports that arrive while fn is busy are coalesced into the most recent one.
+// It returns a function that stops the subscription. Promises without progress never call fn.
+func OnProgress[P, T any](p *Promise[T], fn func(P)) (unsubscribe func()) {
+	if p.progress == nil {
+		return func() {}
+	}
+	return p.progress.subscribe(func(value any) {
+		if progress, ok := value.(P); ok {
+			fn(progress)
+		}
+	}, func() {})
+}
+
+// Progress returns a channel that receives the progress reports of p.
+// The channel holds only the most recent report and is closed once the Promise settles.
+// For promises without progress the channel is closed right away.
+func Progress[P, T any](p *Promise[T]) <-chan P {
+	ch := make(chan P, 1)
+	if p.progress == nil {
+		close(ch)
+		return ch
+	}
+	p.progress.subscribe(func(value any) {
+		progress, ok := value.(P)
+		if !ok {
+			return
+		}
+		for {
+			select {
+			case ch <- progress:
+				return
+			default:
+			}
+			// Drop the stale report the consumer has not picked up yet
+			select {
+			case <-ch:
+			default:
+			}
+		}
+	}, func() {
+		close(ch)
+	})
+	return ch
+}
@@ -0,0 +1,107 @@
+package promise4g
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgress(t *testing.T) {
+	t.Run("Channel", func(t *testing.T) {
+		ctx := context.Background()
+		next := make(chan struct{})
+		p := NewWithProgress(func(resolve func(string), reject func(error), report func(int)) {
+			for i := 1; i <= 3; i++ {
+				report(i * 10)
+				<-next
+			}
+			resolve("imported")
+		})
+
+		progress := Progress[int](p)
+		var seen []int
+		for i := 0; i < 3; i++ {
+			seen = append(seen, <-progress)
+			next <- struct{}{}
+		}
+		require.Equal(t, []int{10, 20, 30}, seen)
+
+		result, err := p.Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "imported", result)
+		_, ok := <-progress
+		require.False(t, ok)
+	})
+
+	t.Run("SlowSubscriberDoesNotBlock", func(t *testing.T) {
+		ctx := context.Background()
+		p := NewWithProgress(func(resolve func(int), reject func(error), report func(int)) {
+			for i := 1; i <= 1000; i++ {
+				report(i)
+			}
+			resolve(0)
+		})
+		progress := Progress[int](p)
+
+		_, err := p.Await(ctx)
+		require.NoError(t, err)
+		var last int
+		for v := range progress {
+			last = v
+		}
+		require.Equal(t, 1000, last)
+	})
+
+	t.Run("Then", func(t *testing.T) {
+		ctx := context.Background()
+		reported := make(chan string, 1)
+		seen := make(chan string, 1)
+		p := NewWithProgress(func(resolve func(int), reject func(error), report func(string)) {
+			report("halfway")
+			// Wait for the report to reach the chained promise before settling
+			seen <- <-reported
+			resolve(1)
+		})
+		chained := Then(p, ctx, func(v int) (string, error) {
+			return fmt.Sprint(v), nil
+		})
+
+		unsubscribe := OnProgress(chained, func(progress string) {
+			reported <- progress
+		})
+		defer unsubscribe()
+
+		result, err := chained.Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "1", result)
+		require.Equal(t, "halfway", <-seen)
+	})
+
+	t.Run("NoProgress", func(t *testing.T) {
+		p := New(func(resolve func(int), reject func(error)) {
+			resolve(1)
+		})
+		_, ok := <-Progress[int](p)
+		require.False(t, ok)
+	})
+
+	t.Run("RejectedByPool", func(t *testing.T) {
+		pool := NewManagedPool(nil)
+		require.NoError(t, pool.Shutdown(context.Background()))
+		p := NewWithProgress(func(resolve func(int), reject func(error), report func(int)) {
+			resolve(1)
+		}, WithPool(pool))
+
+		_, err := p.Await(context.Background())
+		require.ErrorIs(t, err, ErrPoolClosed)
+		select {
+		case _, ok := <-Progress[int](p):
+			require.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("progress was not closed")
+		}
+	})
+}
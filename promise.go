@@ -2,7 +2,9 @@ package promise4g
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"runtime/pprof"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -15,6 +17,25 @@ type Promise[T any] struct {
 	done      chan struct{}
 	once      sync.Once
 	startTime time.Time
+	clock     Clock
+	runStart  atomic.Int64 // UnixNano of when the task started running
+	pool      Pool
+	debugID   uint64
+	progress  *progressHub
+	closeHub  bool // set on the Promise that created progress, which closes it when it settles
+	labels    []string
+	log       *promiseLog
+
+	callbacksMu     sync.Mutex
+	callbacks       map[uint64]func()
+	nextCallbackID  uint64
+	callbacksCalled bool
+}
+
+// Result holds the outcome of a settled Promise
+type Result[T any] struct {
+	Value T
+	Err   error
 }
 
 // New creates a new Promise with the given task
@@ -24,24 +45,103 @@ func New[T any](task func(resolve func(T), reject func(error))) *Promise[T] {
 
 // NewWithPool creates a new Promise with the given task and pool
 func NewWithPool[T any](task func(resolve func(T), reject func(error)), pool Pool) *Promise[T] {
+	return NewWithOptions(task, WithPool(pool))
+}
+
+// NewWithOptions creates a new Promise with the given task and options
+func NewWithOptions[T any](task func(resolve func(T), reject func(error)), opts ...Option) *Promise[T] {
 	if task == nil {
 		panic("task must not be nil")
 	}
-	if pool == nil {
+	o := newOptions(opts)
+	if o.pool == nil {
 		panic("pool must not be nil")
 	}
+	p := newPromise[T](o)
+	p.submit(o.ctx, o.pool, o.weight, task)
+	return p
+}
+
+// newPromise creates a pending Promise that is settled by the caller rather than by a task
+func newPromise[T any](o *options) *Promise[T] {
 	incrementPromisesCreated()
-	incrementConcurrentPromises()
 	p := &Promise[T]{
 		done:      make(chan struct{}),
-		startTime: time.Now(),
+		startTime: o.clock.Now(),
+		clock:     o.clock,
+		pool:      o.pool,
+		labels:    o.pprofLabels(),
+		log:       newPromiseLog(o),
+	}
+	p.debugID = registerPending(o)
+	return p
+}
+
+// rejected returns a Promise that is already rejected with err, without scheduling any work
+func rejected[T any](err error) *Promise[T] {
+	p := newPromise[T](newOptions(nil))
+	p.reject(err)
+	return p
+}
+
+// run schedules the task that settles the Promise on the given pool
+func (p *Promise[T]) run(pool Pool, task func(resolve func(T), reject func(error))) {
+	p.submit(context.Background(), pool, 1, task)
+}
+
+// submit schedules the task that settles the Promise on the given pool.
+// Pools that admit tasks themselves may delay the task while ctx allows, or reject the Promise instead.
+func (p *Promise[T]) submit(ctx context.Context, pool Pool, weight int64, task func(resolve func(T), reject func(error))) {
+	incrementConcurrentPromises()
+	// A ManagedPool has the task count itself in and out rather than wrapping it,
+	// which saves two allocations for every promise on the ants and conc adapters
+	managed, _ := pool.(*ManagedPool)
+	submitted := false
+	if managed != nil {
+		if !managed.acquire() {
+			decrementConcurrentPromises()
+			p.reject(ErrPoolClosed)
+			return
+		}
+		pool = managed.pool
+		defer func() {
+			// The pool panicked instead of taking the task
+			if !submitted {
+				managed.done()
+			}
+		}()
 	}
-	pool.Go(func() {
+
+	work := func() {
+		if managed != nil {
+			defer managed.done()
+		}
+		p.runStart.Store(p.clock.Now().UnixNano())
 		defer p.handlePanic()
 		defer decrementConcurrentPromises()
 		task(p.resolve, p.reject)
-	})
-	return p
+	}
+	if len(p.labels) > 0 {
+		unlabeled := work
+		work = func() {
+			pprof.Do(ctx, pprof.Labels(p.labels...), func(context.Context) {
+				unlabeled()
+			})
+		}
+	}
+	if a, ok := pool.(admitter); ok {
+		a.admit(ctx, weight, work, func(err error) {
+			if managed != nil {
+				managed.done()
+			}
+			decrementConcurrentPromises()
+			p.reject(err)
+		})
+		submitted = true
+		return
+	}
+	pool.Go(work)
+	submitted = true
 }
 
 // Await waits for the Promise to be resolved or rejected
@@ -51,6 +151,7 @@ func (p *Promise[T]) Await(ctx context.Context) (T, error) {
 		var t T
 		return t, ctx.Err()
 	case <-p.done:
+		p.markHandled()
 		if err := p.err.Load(); err != nil {
 			var t T
 			return t, err.(error)
@@ -59,32 +160,122 @@ func (p *Promise[T]) Await(ctx context.Context) (T, error) {
 	}
 }
 
+// markHandled records that the outcome of the Promise is taken care of, either by a caller of Await
+// or by the Promise or iterator it was handed to, so that its rejection is not logged as unhandled
+func (p *Promise[T]) markHandled() {
+	if p.log != nil {
+		p.log.handle()
+	}
+}
+
+// settled reports whether the Promise has been resolved or rejected
+func (p *Promise[T]) settled() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
 func (p *Promise[T]) resolve(value T) {
 	p.once.Do(func() {
 		p.value.Store(value)
-		observePromiseExecutionTime(time.Since(p.startTime).Seconds())
-		close(p.done)
+		p.settle(nil)
 	})
 }
 
 func (p *Promise[T]) reject(err error) {
 	p.once.Do(func() {
 		p.err.Store(err)
-		observePromiseExecutionTime(time.Since(p.startTime).Seconds())
-		close(p.done)
+		p.settle(err)
 	})
 }
 
+// settle finishes settling the Promise once its outcome has been stored
+func (p *Promise[T]) settle(err error) {
+	unregisterPending(p.debugID)
+	now := p.clock.Now()
+	observePromiseExecutionTime(now.Sub(p.startTime).Seconds())
+	if p.log != nil {
+		p.log.settled(now.Sub(p.startTime), err)
+	}
+	if observer, ok := p.pool.(executionObserver); ok {
+		start := p.startTime
+		if runStart := p.runStart.Load(); runStart != 0 {
+			start = time.Unix(0, runStart)
+		}
+		observer.observeExecution(now.Sub(start), err)
+	}
+	close(p.done)
+	if p.closeHub {
+		p.progress.close()
+	}
+
+	p.callbacksMu.Lock()
+	callbacks := p.callbacks
+	p.callbacks = nil
+	p.callbacksCalled = true
+	p.callbacksMu.Unlock()
+	for _, callback := range callbacks {
+		p.dispatch(callback)
+	}
+}
+
+// dispatch runs a settle callback on the pool of the Promise. The pool is called from the default pool,
+// so the worker that settles the Promise never waits for its own pool. Closed pools, pools that admit
+// tasks and adaptive pools are skipped, so callbacks never spend their tokens or capacity.
+func (p *Promise[T]) dispatch(callback func()) {
+	if !runsCallbacks(p.pool) {
+		defaultPool.Go(callback)
+		return
+	}
+	defaultPool.Go(func() {
+		var once sync.Once
+		run := func() {
+			once.Do(callback)
+		}
+		defer func() {
+			// The pool refused the callback
+			if r := recover(); r != nil {
+				run()
+			}
+		}()
+		p.pool.Go(run)
+	})
+}
+
+// runsCallbacks reports whether settle callbacks may be handed to pool
+func runsCallbacks(pool Pool) bool {
+	if managed, ok := pool.(*ManagedPool); ok {
+		if managed.Closed() {
+			return false
+		}
+		pool = managed.pool
+	}
+	switch pool.(type) {
+	case admitter, *AdaptivePool:
+		return false
+	}
+	return true
+}
+
 func (p *Promise[T]) handlePanic() {
 	if r := recover(); r != nil {
-		var err error
-		switch v := r.(type) {
-		case error:
-			err = v
-		default:
-			err = fmt.Errorf("%v", v)
+		if p.log != nil {
+			p.log.panicked(p.clock.Now().Sub(p.startTime), r)
 		}
-		p.reject(err)
+		p.reject(panicToError(r))
+	}
+}
+
+// panicToError converts a recovered panic value into an error
+func panicToError(r any) error {
+	switch v := r.(type) {
+	case error:
+		return v
+	default:
+		return fmt.Errorf("%v", v)
 	}
 }
 
@@ -99,27 +290,7 @@ func AllWithPool[T any](ctx context.Context, pool Pool, promises ...*Promise[T])
 		panic("missing promises")
 	}
 
-	return NewWithPool(func(resolve func([]T), reject func(error)) {
-		results := make([]T, len(promises))
-		var wg sync.WaitGroup
-		wg.Add(len(promises))
-
-		for i, p := range promises {
-			i, p := i, p
-			pool.Go(func() {
-				defer wg.Done()
-				result, err := p.Await(ctx)
-				if err != nil {
-					reject(err)
-					return
-				}
-				results[i] = result
-			})
-		}
-
-		wg.Wait()
-		resolve(results)
-	}, pool)
+	return all(ctx, promises, allOptions{pool: pool})
 }
 
 // Race returns a promise that resolves or rejects as soon as one of the promises resolves or rejects
@@ -127,6 +298,7 @@ func Race[T any](ctx context.Context, promises ...*Promise[T]) *Promise[T] {
 	return NewWithPool(func(resolve func(T), reject func(error)) {
 		for _, p := range promises {
 			p := p // Create a new variable to avoid closure issues
+			p.markHandled()
 			defaultPool.Go(func() {
 				result, err := p.Await(ctx)
 				if err != nil {
@@ -146,7 +318,7 @@ func Then[A, B any](p *Promise[A], ctx context.Context, resolve func(A) (B, erro
 
 // ThenWithPool chains a new Promise to the current one using the given pool
 func ThenWithPool[A, B any](p *Promise[A], ctx context.Context, resolve func(A) (B, error), pool Pool) *Promise[B] {
-	return NewWithPool(func(resolveB func(B), reject func(error)) {
+	return inheritProgress(p, NewWithPool(func(resolveB func(B), reject func(error)) {
 		result, err := p.Await(ctx)
 		if err != nil {
 			reject(err)
@@ -160,7 +332,7 @@ func ThenWithPool[A, B any](p *Promise[A], ctx context.Context, resolve func(A)
 		}
 
 		resolveB(resultB)
-	}, pool)
+	}, pool))
 }
 
 // Catch handles errors in the Promise chain
@@ -170,41 +342,68 @@ func Catch[T any](p *Promise[T], ctx context.Context, reject func(error) error)
 
 // CatchWithPool handles errors in the Promise chain using the given pool
 func CatchWithPool[T any](p *Promise[T], ctx context.Context, reject func(error) error, pool Pool) *Promise[T] {
-	return NewWithPool(func(resolve func(T), internalReject func(error)) {
+	return inheritProgress(p, NewWithPool(func(resolve func(T), internalReject func(error)) {
 		result, err := p.Await(ctx)
 		if err != nil {
 			internalReject(reject(err))
 		} else {
 			resolve(result)
 		}
-	}, pool)
+	}, pool))
 }
 
 // Finally executes a function regardless of whether the promise is fulfilled or rejected
 func Finally[T any](p *Promise[T], ctx context.Context, fn func()) *Promise[T] {
-	return NewWithPool(func(resolve func(T), reject func(error)) {
-		result, err := p.Await(ctx)
+	return FinallyWith(p, ctx, func(T, error) error {
 		fn()
-		if err != nil {
+		return nil
+	})
+}
+
+// FinallyWith executes fn with the outcome of the promise regardless of whether it is fulfilled or rejected.
+// An error returned by fn, or a panic in it, is joined with the error of the promise using errors.Join.
+// The new Promise runs on the pool given with WithPool, or on the default pool.
+func FinallyWith[T any](p *Promise[T], ctx context.Context, fn func(T, error) error, opts ...Option) *Promise[T] {
+	return inheritProgress(p, NewWithOptions(func(resolve func(T), reject func(error)) {
+		result, err := p.Await(ctx)
+		if cleanupErr := callFinally(fn, result, err); cleanupErr != nil {
+			reject(errors.Join(err, cleanupErr))
+		} else if err != nil {
 			reject(err)
 		} else {
 			resolve(result)
 		}
-	}, defaultPool)
+	}, opts...))
 }
 
-// Timeout returns a new Promise that rejects if the original Promise doesn't resolve within the specified duration
+// callFinally runs the FinallyWith callback, turning a panic into an error so that the original outcome is kept
+func callFinally[T any](fn func(T, error) error, result T, err error) (cleanupErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			cleanupErr = panicToError(r)
+		}
+	}()
+	return fn(result, err)
+}
+
+// Timeout returns a new Promise that rejects if the original Promise doesn't resolve within the specified duration.
+// The duration is measured with the clock of the original Promise.
 func Timeout[T any](p *Promise[T], d time.Duration) *Promise[T] {
-	ctx, cancel := context.WithTimeout(context.Background(), d)
-	return NewWithPool(func(resolve func(T), reject func(error)) {
-		defer cancel()
-		result, err := p.Await(ctx)
-		if err != nil {
-			reject(err)
-		} else {
-			resolve(result)
+	timer := p.clock.NewTimer(d)
+	return NewWithOptions(func(resolve func(T), reject func(error)) {
+		defer timer.Stop()
+		select {
+		case <-timer.C():
+			reject(context.DeadlineExceeded)
+		case <-p.done:
+			result, err := p.Await(context.Background())
+			if err != nil {
+				reject(err)
+			} else {
+				resolve(result)
+			}
 		}
-	}, defaultPool)
+	}, WithClock(p.clock))
 }
 
 // AsyncTask creates a new Promise that executes the provided function asynchronously.
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"runtime/pprof"
+	"strings"
 	"testing"
 	"time"
 
@@ -604,3 +606,128 @@ func TestPromise_AllHTTPCalls(t *testing.T) {
 	require.Equal(t, "username", res2.Username)
 	require.Equal(t, "requestId 2", res2.RequestId)
 }
+
+func TestPromise_FinallyWith(t *testing.T) {
+	t.Run("ReceivesOutcome", func(t *testing.T) {
+		ctx := context.Background()
+		p := New(func(resolve func(string), reject func(error)) {
+			resolve("success")
+		})
+
+		var got string
+		result, err := FinallyWith(p, ctx, func(v string, err error) error {
+			got = v
+			return err
+		}).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "success", result)
+		require.Equal(t, "success", got)
+	})
+
+	t.Run("JoinsCleanupError", func(t *testing.T) {
+		ctx := context.Background()
+		original := errors.New("error")
+		cleanup := errors.New("close failed")
+		p := New(func(resolve func(string), reject func(error)) {
+			reject(original)
+		})
+
+		_, err := FinallyWith(p, ctx, func(string, error) error {
+			return cleanup
+		}).Await(ctx)
+		require.ErrorIs(t, err, original)
+		require.ErrorIs(t, err, cleanup)
+	})
+
+	t.Run("CleanupErrorAfterResolve", func(t *testing.T) {
+		ctx := context.Background()
+		cleanup := errors.New("close failed")
+		p := New(func(resolve func(string), reject func(error)) {
+			resolve("success")
+		})
+
+		_, err := FinallyWith(p, ctx, func(string, error) error {
+			return cleanup
+		}).Await(ctx)
+		require.ErrorIs(t, err, cleanup)
+		require.EqualError(t, err, "close failed")
+	})
+
+	t.Run("CleanupPanicAfterResolve", func(t *testing.T) {
+		ctx := context.Background()
+		p := New(func(resolve func(string), reject func(error)) {
+			resolve("success")
+		})
+
+		_, err := FinallyWith(p, ctx, func(string, error) error {
+			panic("close failed")
+		}).Await(ctx)
+		require.EqualError(t, err, "close failed")
+	})
+
+	t.Run("WithPool", func(t *testing.T) {
+		ctx := context.Background()
+		pool, err := ants.NewPool(1)
+		require.NoError(t, err)
+		p := New(func(resolve func(int), reject func(error)) {
+			resolve(1)
+		})
+
+		result, err := FinallyWith(p, ctx, func(int, error) error {
+			return nil
+		}, WithPool(FromAntsPool(pool))).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, result)
+	})
+}
+
+func TestPromise_Labels(t *testing.T) {
+	t.Run("RunsUnderPprofLabels", func(t *testing.T) {
+		ctx := context.Background()
+		started := make(chan struct{})
+		release := make(chan struct{})
+		p := NewWithOptions(func(resolve func(int), reject func(error)) {
+			close(started)
+			<-release
+			resolve(1)
+		}, WithName("fetch-user"), WithLabels("tenant", "acme"))
+		<-started
+
+		var dump strings.Builder
+		require.NoError(t, pprof.Lookup("goroutine").WriteTo(&dump, 1))
+		close(release)
+		require.Contains(t, dump.String(), `"promise":"fetch-user"`)
+		require.Contains(t, dump.String(), `"tenant":"acme"`)
+
+		result, err := p.Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, result)
+	})
+
+	t.Run("KeepsContextLabels", func(t *testing.T) {
+		ctx := pprof.WithLabels(context.Background(), pprof.Labels("service", "billing"))
+		started := make(chan struct{})
+		release := make(chan struct{})
+		p := NewWithOptions(func(resolve func(int), reject func(error)) {
+			close(started)
+			<-release
+			resolve(1)
+		}, WithContext(ctx), WithName("charge"))
+		<-started
+
+		var dump strings.Builder
+		require.NoError(t, pprof.Lookup("goroutine").WriteTo(&dump, 1))
+		close(release)
+		require.Contains(t, dump.String(), `"promise":"charge"`)
+		require.Contains(t, dump.String(), `"service":"billing"`)
+
+		_, err := p.Await(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("OddLabels", func(t *testing.T) {
+		require.Panics(t, func() {
+			WithLabels("tenant")
+		})
+	})
+}
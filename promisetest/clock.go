@@ -0,0 +1,159 @@
+package promisetest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hoanguyenkh/promise4g"
+)
+
+var _ promise4g.Clock = (*FakeClock)(nil)
+
+// FakeClock is a promise4g.Clock whose time only moves when told to
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFakeClock creates a new FakeClock set to the given time
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the current fake time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives the fake time once the clock has advanced by d
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// NewTimer creates a timer that fires once the clock has advanced by d
+func (c *FakeClock) NewTimer(d time.Duration) promise4g.Timer {
+	t := &fakeTimer{clock: c, ch: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+// AfterFunc creates a timer that calls f once the clock has advanced by d.
+// f runs on the goroutine that advances the clock.
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) promise4g.Timer {
+	t := &fakeTimer{clock: c, fn: f}
+	t.Reset(d)
+	return t
+}
+
+// Timers returns the number of timers that have not fired or been stopped
+func (c *FakeClock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// BlockUntil waits until at least n timers are pending.
+// It lets a test wait for a goroutine to start waiting on the clock before advancing it.
+func (c *FakeClock) BlockUntil(n int) {
+	for c.Timers() < n {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Advance moves the clock forward by d, firing every timer that is due in order
+func (c *FakeClock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the clock to t, firing every timer that is due in order
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	c.now = t
+	c.mu.Unlock()
+
+	for {
+		timer := c.nextDue(t)
+		if timer == nil {
+			return
+		}
+		if timer.fn != nil {
+			timer.fn()
+		} else {
+			select {
+			case timer.ch <- t:
+			default:
+			}
+		}
+	}
+}
+
+// nextDue removes and returns the earliest timer due at now, if any
+func (c *FakeClock) nextDue(now time.Time) *fakeTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sort.SliceStable(c.timers, func(i, j int) bool {
+		return c.timers[i].at.Before(c.timers[j].at)
+	})
+	if len(c.timers) == 0 || c.timers[0].at.After(now) {
+		return nil
+	}
+	timer := c.timers[0]
+	c.timers = c.timers[1:]
+	return timer
+}
+
+// remove unregisters the timer and reports whether it was pending. c.mu must be held.
+func (c *FakeClock) remove(t *fakeTimer) bool {
+	for i, timer := range c.timers {
+		if timer == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTimer struct {
+	clock *FakeClock
+	at    time.Time
+	ch    chan time.Time
+	fn    func()
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	c := t.clock
+	c.mu.Lock()
+	active := c.remove(t)
+	t.at = c.now.Add(d)
+	if d > 0 || t.fn != nil {
+		c.timers = append(c.timers, t)
+		c.mu.Unlock()
+		if d <= 0 {
+			c.Set(c.Now())
+		}
+		return active
+	}
+	now := c.now
+	c.mu.Unlock()
+
+	select {
+	case t.ch <- now:
+	default:
+	}
+	return active
+}
@@ -0,0 +1,57 @@
+// Package promisetest provides a manual pool and a fake clock for testing code built on promise4g
+// without depending on goroutine scheduling or real time.
+package promisetest
+
+import "sync"
+
+// ManualPool is a promise4g.Pool that queues tasks until they are run explicitly.
+// Tasks run on the goroutine that calls RunNext or RunAll, so a task that waits for another task
+// of the same pool blocks forever. Use it for leaf promises and keep combinators such as All on a real pool.
+type ManualPool struct {
+	mu    sync.Mutex
+	queue []func()
+}
+
+// NewManualPool creates a new empty ManualPool
+func NewManualPool() *ManualPool {
+	return &ManualPool{}
+}
+
+// Go queues f until RunNext or RunAll is called
+func (p *ManualPool) Go(f func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queue = append(p.queue, f)
+}
+
+// Len returns the number of queued tasks
+func (p *ManualPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.queue)
+}
+
+// RunNext runs the oldest queued task on the calling goroutine and reports whether there was one
+func (p *ManualPool) RunNext() bool {
+	p.mu.Lock()
+	if len(p.queue) == 0 {
+		p.mu.Unlock()
+		return false
+	}
+	f := p.queue[0]
+	p.queue = p.queue[1:]
+	p.mu.Unlock()
+
+	f()
+	return true
+}
+
+// RunAll runs queued tasks, including the ones they queue, until the queue is empty.
+// It returns the number of tasks run.
+func (p *ManualPool) RunAll() int {
+	n := 0
+	for p.RunNext() {
+		n++
+	}
+	return n
+}
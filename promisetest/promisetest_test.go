@@ -0,0 +1,131 @@
+package promisetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hoanguyenkh/promise4g"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManualPool(t *testing.T) {
+	ctx := context.Background()
+	pool := NewManualPool()
+	p1 := promise4g.NewWithPool(func(resolve func(int), reject func(error)) {
+		resolve(1)
+	}, pool)
+	p2 := promise4g.NewWithPool(func(resolve func(int), reject func(error)) {
+		resolve(2)
+	}, pool)
+	all := promise4g.All(ctx, p1, p2)
+	require.Equal(t, 2, pool.Len())
+
+	require.True(t, pool.RunNext())
+	select {
+	case <-all.Chan():
+		t.Fatal("All settled before its inputs")
+	default:
+	}
+
+	require.Equal(t, 1, pool.RunAll())
+	results, err := all.Await(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, results)
+	require.False(t, pool.RunNext())
+}
+
+func TestFakeClock(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	promise4g.SetClock(clock)
+	defer promise4g.SetClock(nil)
+
+	t.Run("TimeoutExpires", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		p := promise4g.New(func(resolve func(string), reject func(error)) {
+			<-release
+			resolve("too late")
+		})
+
+		timeout := promise4g.Timeout(p, time.Minute)
+		clock.Advance(time.Minute)
+		_, err := timeout.Await(context.Background())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("ResolveBeforeTimeout", func(t *testing.T) {
+		p := promise4g.New(func(resolve func(string), reject func(error)) {
+			resolve("on time")
+		})
+
+		result, err := promise4g.Timeout(p, time.Minute).Await(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "on time", result)
+	})
+
+	t.Run("After", func(t *testing.T) {
+		ch := clock.After(time.Second)
+		clock.Advance(500 * time.Millisecond)
+		select {
+		case <-ch:
+			t.Fatal("fired too early")
+		default:
+		}
+		clock.Advance(500 * time.Millisecond)
+		require.Equal(t, time.Unix(61, 0), <-ch)
+	})
+}
+
+func TestFakeClock_Timers(t *testing.T) {
+	t.Run("AfterFunc", func(t *testing.T) {
+		clock := NewFakeClock(time.Unix(0, 0))
+		var fired []int
+		clock.AfterFunc(2*time.Second, func() { fired = append(fired, 2) })
+		clock.AfterFunc(time.Second, func() { fired = append(fired, 1) })
+		stopped := clock.AfterFunc(time.Second, func() { fired = append(fired, 3) })
+		require.True(t, stopped.Stop())
+
+		clock.Advance(3 * time.Second)
+		require.Equal(t, []int{1, 2}, fired)
+		require.Equal(t, 0, clock.Timers())
+	})
+
+	t.Run("WithClockTimeout", func(t *testing.T) {
+		clock := NewFakeClock(time.Unix(0, 0))
+		release := make(chan struct{})
+		defer close(release)
+		p := promise4g.NewWithOptions(func(resolve func(int), reject func(error)) {
+			<-release
+		}, promise4g.WithClock(clock))
+
+		timeout := promise4g.Timeout(p, time.Second)
+		clock.Advance(time.Second)
+		_, err := timeout.Await(context.Background())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("ContextWithClockHedge", func(t *testing.T) {
+		clock := NewFakeClock(time.Unix(0, 0))
+		ctx := promise4g.ContextWithClock(context.Background(), clock)
+		attempts := 0
+		p := promise4g.Hedge(ctx, func(ctx context.Context) *promise4g.Promise[int] {
+			attempts++
+			n := attempts
+			return promise4g.New(func(resolve func(int), reject func(error)) {
+				if n == 1 {
+					<-ctx.Done()
+					reject(ctx.Err())
+					return
+				}
+				resolve(n)
+			})
+		}, time.Hour, 1)
+
+		clock.BlockUntil(1)
+		clock.Advance(time.Hour)
+		result, err := p.Await(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 2, result)
+	})
+}
@@ -0,0 +1,193 @@
+package promise4g
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter holds task starts until they are allowed to proceed
+type Limiter interface {
+	// Wait blocks until a task may start or ctx is done
+	Wait(ctx context.Context) error
+}
+
+// TokenBucket is a Limiter that allows rate tasks per second with bursts of up to burst tasks
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	clock  Clock
+}
+
+// NewTokenBucket creates a full TokenBucket refilled at rate tokens per second
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	return NewTokenBucketWithClock(rate, burst, getClock())
+}
+
+// NewTokenBucketWithClock creates a full TokenBucket refilled at rate tokens per second as measured by the given clock
+func NewTokenBucketWithClock(rate float64, burst int, clock Clock) *TokenBucket {
+	if clock == nil {
+		panic("clock must not be nil")
+	}
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		panic("rate must be a positive number")
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   clock.Now(),
+		clock:  clock,
+	}
+}
+
+// Allow takes a token if one is available right now
+func (tb *TokenBucket) Allow() bool {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.advance(tb.clock.Now())
+	if tb.tokens < 1 {
+		return false
+	}
+	tb.tokens--
+	return true
+}
+
+// Wait takes a token, waiting for one to become available if needed
+func (tb *TokenBucket) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	delay := tb.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := tb.clock.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		tb.unreserve()
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token, possibly going into debt, and returns how long to wait before using it
+func (tb *TokenBucket) reserve() time.Duration {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.advance(tb.clock.Now())
+	tb.tokens--
+	if tb.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-tb.tokens / tb.rate * float64(time.Second))
+}
+
+// unreserve gives back a token taken by a cancelled Wait
+func (tb *TokenBucket) unreserve() {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.tokens = math.Min(tb.tokens+1, tb.burst)
+}
+
+func (tb *TokenBucket) advance(now time.Time) {
+	elapsed := now.Sub(tb.last).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	tb.last = now
+	tb.tokens = math.Min(tb.tokens+elapsed*tb.rate, tb.burst)
+}
+
+// RateLimitPool decorates pool so that every task waits for the limiter before it starts.
+// Tasks wait without holding a worker of pool, and promises bound their wait with WithContext.
+// Promises are rejected with the error of the limiter if it refuses them.
+// The name identifies the pool in metrics.
+func RateLimitPool(name string, pool Pool, limiter Limiter) Pool {
+	if pool == nil {
+		panic("pool must not be nil")
+	}
+	if limiter == nil {
+		panic("limiter must not be nil")
+	}
+	return &rateLimitedPool{name: name, pool: pool, limiter: limiter}
+}
+
+type rateLimitedPool struct {
+	name    string
+	pool    Pool
+	limiter Limiter
+}
+
+// Go runs f once the limiter allows it. If the limiter or the underlying pool refuses f after it
+// has waited, f is dropped and logged.
+func (rp *rateLimitedPool) Go(f func()) {
+	goAdmitted(rp, rp.admit, f)
+}
+
+func (rp *rateLimitedPool) String() string {
+	return "rate-limited " + rp.name
+}
+
+func (rp *rateLimitedPool) admit(ctx context.Context, weight int64, run func(), fail func(error)) {
+	start := func() {
+		if a, ok := rp.pool.(admitter); ok {
+			a.admit(ctx, weight, run, fail)
+			return
+		}
+		startOn(rp.pool, run, fail)
+	}
+	if tb, ok := rp.limiter.(*TokenBucket); ok && tb.Allow() {
+		observeRateLimitWait(rp.name, 0)
+		start()
+		return
+	}
+
+	go func() {
+		clock := ClockFromContext(ctx)
+		waitStart := clock.Now()
+		err := rp.limiter.Wait(ctx)
+		observeRateLimitWait(rp.name, clock.Now().Sub(waitStart).Seconds())
+		if err != nil {
+			fail(err)
+			return
+		}
+		start()
+	}()
+}
+
+// RateLimit calls fn once the limiter allows it.
+// It rejects with the context error if ctx is done before that happens.
+func RateLimit[T any](ctx context.Context, limiter Limiter, fn func(ctx context.Context) *Promise[T]) *Promise[T] {
+	return NewWithPool(func(resolve func(T), reject func(error)) {
+		clock := ClockFromContext(ctx)
+		start := clock.Now()
+		err := limiter.Wait(ctx)
+		observeRateLimitWait("", clock.Now().Sub(start).Seconds())
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		result, err := fn(ctx).Await(ctx)
+		if err != nil {
+			reject(err)
+		} else {
+			resolve(result)
+		}
+	}, defaultPool)
+}
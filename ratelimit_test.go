@@ -0,0 +1,119 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/panjf2000/ants/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket(t *testing.T) {
+	t.Run("Burst", func(t *testing.T) {
+		tb := NewTokenBucket(1, 2)
+		require.True(t, tb.Allow())
+		require.True(t, tb.Allow())
+		require.False(t, tb.Allow())
+	})
+
+	t.Run("WaitCanceled", func(t *testing.T) {
+		tb := NewTokenBucket(1, 1)
+		require.True(t, tb.Allow())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, tb.Wait(ctx), context.DeadlineExceeded)
+	})
+}
+
+func TestRateLimitPool(t *testing.T) {
+	ctx := context.Background()
+	antsPool, err := ants.NewPool(0)
+	require.NoError(t, err)
+	pool := RateLimitPool("limited", FromAntsPool(antsPool), NewTokenBucket(50, 1))
+
+	start := time.Now()
+	promises := make([]*Promise[int], 5)
+	for i := range promises {
+		i := i
+		promises[i] = NewWithPool(func(resolve func(int), reject func(error)) {
+			resolve(i)
+		}, pool)
+	}
+	results, err := All(ctx, promises...).Await(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2, 3, 4}, results)
+	require.GreaterOrEqual(t, time.Since(start), 70*time.Millisecond, "Tasks were not rate limited")
+}
+
+func TestRateLimitPool_WithContext(t *testing.T) {
+	antsPool, err := ants.NewPool(1)
+	require.NoError(t, err)
+	defer antsPool.Release()
+	tb := NewTokenBucket(1, 1)
+	require.True(t, tb.Allow())
+	pool := RateLimitPool("with-context", FromAntsPool(antsPool), tb)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	ran := false
+	p := NewWithOptions(func(resolve func(int), reject func(error)) {
+		ran = true
+		resolve(1)
+	}, WithPool(pool), WithContext(ctx))
+	require.Equal(t, 0, antsPool.Running(), "a worker was taken while waiting for a token")
+
+	_, err = p.Await(context.Background())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.False(t, ran)
+}
+
+type refusingLimiter struct{}
+
+func (refusingLimiter) Wait(context.Context) error {
+	return errors.New("burst exceeded")
+}
+
+func TestRateLimitPool_Refused(t *testing.T) {
+	ctx := context.Background()
+	t.Run("Limiter", func(t *testing.T) {
+		pool := RateLimitPool("refusing", newDefaultPool(), refusingLimiter{})
+		_, err := NewWithPool(func(resolve func(int), reject func(error)) {
+			resolve(1)
+		}, pool).Await(ctx)
+		require.EqualError(t, err, "burst exceeded")
+
+		// Nobody can be told, so the task is dropped rather than crashing the process
+		pool.Go(func() {
+			t.Error("refused task ran")
+		})
+	})
+
+	t.Run("Pool", func(t *testing.T) {
+		inner := NewManagedPool(nil)
+		require.NoError(t, inner.Shutdown(ctx))
+		pool := RateLimitPool("closed", inner, NewTokenBucket(1, 1))
+		_, err := NewWithPool(func(resolve func(int), reject func(error)) {
+			resolve(1)
+		}, pool).Await(ctx)
+		require.ErrorIs(t, err, ErrPoolClosed)
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	tb := NewTokenBucket(1, 1)
+	require.True(t, tb.Allow())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	called := false
+	p := RateLimit(ctx, tb, func(ctx context.Context) *Promise[int] {
+		called = true
+		return AsyncTask(func() (int, error) { return 1, nil })
+	})
+	_, err := p.Await(context.Background())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.False(t, called)
+}
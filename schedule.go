@@ -0,0 +1,170 @@
+package promise4g
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Delay returns a Promise that resolves with value after d, measured with the clock of ctx.
+// No goroutine is held while waiting. It rejects with the context error if ctx is done first.
+func Delay[T any](ctx context.Context, d time.Duration, value T) *Promise[T] {
+	clock := ClockFromContext(ctx)
+	p := newPromise[T](newOptions([]Option{WithClock(clock)}))
+	schedule(ctx, p, clock, d, func() {
+		p.resolve(value)
+	})
+	return p
+}
+
+// After returns a Promise that resolves after d, measured with the clock of ctx.
+// It rejects with the context error if ctx is done first.
+func After(ctx context.Context, d time.Duration) *Promise[struct{}] {
+	return Delay(ctx, d, struct{}{})
+}
+
+// At returns a Promise that runs task at the given time, as told by the clock of ctx.
+// It rejects with the context error if ctx is done before then.
+func At[T any](ctx context.Context, t time.Time, task func(ctx context.Context) (T, error)) *Promise[T] {
+	clock := ClockFromContext(ctx)
+	p := newPromise[T](newOptions([]Option{WithClock(clock)}))
+	schedule(ctx, p, clock, t.Sub(clock.Now()), func() {
+		p.run(defaultPool, func(resolve func(T), reject func(error)) {
+			result, err := task(ctx)
+			if err != nil {
+				reject(err)
+			} else {
+				resolve(result)
+			}
+		})
+	})
+	return p
+}
+
+// schedule calls fn after d, or rejects p with the context error if ctx is done first
+func schedule[T any](ctx context.Context, p *Promise[T], clock Clock, d time.Duration, fn func()) {
+	if err := ctx.Err(); err != nil {
+		p.reject(err)
+		return
+	}
+
+	// The context hook is registered before the timer, which may fire right away,
+	// and whichever of the two claims the schedule first gets to run
+	var claimed atomic.Bool
+	var mu sync.Mutex
+	var timer Timer
+	stop := context.AfterFunc(ctx, func() {
+		if !claimed.CompareAndSwap(false, true) {
+			return
+		}
+		mu.Lock()
+		if timer != nil {
+			timer.Stop()
+		}
+		mu.Unlock()
+		p.reject(ctx.Err())
+	})
+
+	t := clock.AfterFunc(d, func() {
+		if !claimed.CompareAndSwap(false, true) {
+			return
+		}
+		stop()
+		fn()
+	})
+	mu.Lock()
+	timer = t
+	mu.Unlock()
+	if claimed.Load() {
+		// The context may have been done before the timer was stored
+		t.Stop()
+	}
+}
+
+// Ticker runs a task at a fixed interval and hands out a Promise for every run
+type Ticker[T any] struct {
+	ctx      context.Context
+	clock    Clock
+	interval time.Duration
+	task     func(ctx context.Context) (T, error)
+	ch       chan *Promise[T]
+
+	mu      sync.Mutex
+	timer   Timer
+	last    *Promise[T]
+	stopped bool
+	stopCtx func() bool
+}
+
+// Every runs task every interval, measured with the clock of ctx, until ctx is done or the Ticker is stopped.
+// A tick is skipped while the previous run is still pending or its Promise has not been received from C yet.
+func Every[T any](ctx context.Context, interval time.Duration, task func(ctx context.Context) (T, error)) *Ticker[T] {
+	if interval <= 0 {
+		panic("interval must be positive")
+	}
+	if task == nil {
+		panic("task must not be nil")
+	}
+
+	t := &Ticker[T]{
+		ctx:      ctx,
+		clock:    ClockFromContext(ctx),
+		interval: interval,
+		task:     task,
+		ch:       make(chan *Promise[T], 1),
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.timer = t.clock.AfterFunc(interval, t.tick)
+	t.stopCtx = context.AfterFunc(ctx, t.Stop)
+	return t
+}
+
+// C returns the channel that receives a Promise for every run. It is closed once the Ticker stops.
+func (t *Ticker[T]) C() <-chan *Promise[T] {
+	return t.ch
+}
+
+// Stop stops the Ticker. Runs that have already started are not interrupted.
+func (t *Ticker[T]) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return
+	}
+	t.stopped = true
+	t.timer.Stop()
+	if t.stopCtx != nil {
+		t.stopCtx()
+	}
+	close(t.ch)
+}
+
+func (t *Ticker[T]) tick() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return
+	}
+	t.timer = t.clock.AfterFunc(t.interval, t.tick)
+
+	if t.last != nil && !t.last.settled() {
+		return
+	}
+	if len(t.ch) == cap(t.ch) {
+		return
+	}
+
+	p := newPromise[T](newOptions([]Option{WithClock(t.clock)}))
+	p.run(defaultPool, func(resolve func(T), reject func(error)) {
+		result, err := t.task(t.ctx)
+		if err != nil {
+			reject(err)
+		} else {
+			resolve(result)
+		}
+	})
+	t.last = p
+	t.ch <- p
+}
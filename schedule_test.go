@@ -0,0 +1,150 @@
+package promise4g_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hoanguyenkh/promise4g"
+	"github.com/hoanguyenkh/promise4g/promisetest"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClockContext returns a context whose clock is a new FakeClock
+func fakeClockContext() (context.Context, *promisetest.FakeClock) {
+	clock := promisetest.NewFakeClock(time.Unix(0, 0))
+	return promise4g.ContextWithClock(context.Background(), clock), clock
+}
+
+func TestDelay(t *testing.T) {
+	t.Run("Resolves", func(t *testing.T) {
+		ctx, clock := fakeClockContext()
+		p := promise4g.Delay(ctx, 30*time.Millisecond, "later")
+		clock.Advance(29 * time.Millisecond)
+		require.Empty(t, p.Chan())
+
+		clock.Advance(time.Millisecond)
+		result, err := p.Await(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "later", result)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, clock := fakeClockContext()
+		ctx, cancel := context.WithCancel(ctx)
+		p := promise4g.After(ctx, time.Hour)
+		cancel()
+		_, err := p.Await(context.Background())
+		require.ErrorIs(t, err, context.Canceled)
+		require.Eventually(t, func() bool {
+			return clock.Timers() == 0
+		}, time.Second, time.Millisecond)
+	})
+}
+
+// hookCountingContext is a context that is never done and counts the context.AfterFunc hooks
+// registered on it that are still active
+type hookCountingContext struct {
+	context.Context
+	done   chan struct{}
+	active atomic.Int32
+}
+
+func (c *hookCountingContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *hookCountingContext) AfterFunc(f func()) func() bool {
+	c.active.Add(1)
+	var stopped atomic.Bool
+	return func() bool {
+		if stopped.CompareAndSwap(false, true) {
+			c.active.Add(-1)
+			return true
+		}
+		return false
+	}
+}
+
+func TestSchedule_ReleasesContextHook(t *testing.T) {
+	// A FakeClock fires timers that are already due on the goroutine that creates them
+	for _, d := range []time.Duration{0, time.Millisecond} {
+		clockCtx, clock := fakeClockContext()
+		ctx := &hookCountingContext{
+			Context: clockCtx,
+			done:    make(chan struct{}),
+		}
+		p := promise4g.Delay(ctx, d, 1)
+		clock.Advance(d)
+		_, err := p.Await(context.Background())
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return ctx.active.Load() == 0
+		}, time.Second, time.Millisecond, "context hook leaked for d=%s", d)
+	}
+}
+
+func TestAt(t *testing.T) {
+	ctx, clock := fakeClockContext()
+	p := promise4g.At(ctx, clock.Now().Add(30*time.Millisecond), func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	clock.Advance(29 * time.Millisecond)
+	require.Empty(t, p.Chan())
+
+	clock.Advance(time.Millisecond)
+	result, err := p.Await(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, result)
+
+	_, err = promise4g.At(ctx, clock.Now(), func(ctx context.Context) (int, error) {
+		return 0, errors.New("boom")
+	}).Await(ctx)
+	require.EqualError(t, err, "boom")
+}
+
+func TestEvery(t *testing.T) {
+	t.Run("Ticks", func(t *testing.T) {
+		ctx, clock := fakeClockContext()
+		ctx, cancel := context.WithCancel(ctx)
+		var runs atomic.Int32
+		ticker := promise4g.Every(ctx, 10*time.Millisecond, func(ctx context.Context) (int32, error) {
+			return runs.Add(1), nil
+		})
+
+		for want := int32(1); want <= 3; want++ {
+			clock.Advance(10 * time.Millisecond)
+			result, err := (<-ticker.C()).Await(ctx)
+			require.NoError(t, err)
+			require.Equal(t, want, result)
+		}
+		cancel()
+		for range ticker.C() {
+		}
+	})
+
+	t.Run("SkipsOverlappingRuns", func(t *testing.T) {
+		ctx, clock := fakeClockContext()
+		var runs atomic.Int32
+		release := make(chan struct{})
+		ticker := promise4g.Every(ctx, 5*time.Millisecond, func(ctx context.Context) (int, error) {
+			runs.Add(1)
+			<-release
+			return 0, nil
+		})
+		defer ticker.Stop()
+
+		clock.Advance(5 * time.Millisecond)
+		p := <-ticker.C()
+		for range 10 {
+			clock.Advance(5 * time.Millisecond)
+		}
+		require.Empty(t, ticker.C())
+		close(release)
+		_, err := p.Await(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, int32(1), runs.Load())
+	})
+}
@@ -0,0 +1,66 @@
+package promise4g
+
+import "context"
+
+// Series runs the promise factories one after another and resolves with their results in order.
+// It stops and rejects at the first error or when ctx is done.
+func Series[T any](ctx context.Context, factories ...func(ctx context.Context) *Promise[T]) *Promise[[]T] {
+	return New(func(resolve func([]T), reject func(error)) {
+		results := make([]T, len(factories))
+		for i, factory := range factories {
+			if err := ctx.Err(); err != nil {
+				reject(err)
+				return
+			}
+			result, err := factory(ctx).Await(ctx)
+			if err != nil {
+				reject(err)
+				return
+			}
+			results[i] = result
+		}
+		resolve(results)
+	})
+}
+
+// Waterfall runs the steps one after another, passing the result of each step to the next one.
+// The first step receives init. It stops and rejects at the first error or when ctx is done.
+func Waterfall[T any](ctx context.Context, init T, steps ...func(ctx context.Context, value T) *Promise[T]) *Promise[T] {
+	return New(func(resolve func(T), reject func(error)) {
+		value := init
+		for _, step := range steps {
+			if err := ctx.Err(); err != nil {
+				reject(err)
+				return
+			}
+			result, err := step(ctx, value).Await(ctx)
+			if err != nil {
+				reject(err)
+				return
+			}
+			value = result
+		}
+		resolve(value)
+	})
+}
+
+// ReduceAsync folds items into a single value, waiting for each promise returned by fn before processing the next item.
+// It stops and rejects at the first error or when ctx is done.
+func ReduceAsync[T, A any](ctx context.Context, items []T, init A, fn func(A, T) *Promise[A]) *Promise[A] {
+	return New(func(resolve func(A), reject func(error)) {
+		acc := init
+		for _, item := range items {
+			if err := ctx.Err(); err != nil {
+				reject(err)
+				return
+			}
+			result, err := fn(acc, item).Await(ctx)
+			if err != nil {
+				reject(err)
+				return
+			}
+			acc = result
+		}
+		resolve(acc)
+	})
+}
@@ -0,0 +1,76 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeries(t *testing.T) {
+	t.Run("InOrder", func(t *testing.T) {
+		ctx := context.Background()
+		var order []int
+		step := func(i int) func(ctx context.Context) *Promise[int] {
+			return func(ctx context.Context) *Promise[int] {
+				return AsyncTask(func() (int, error) {
+					order = append(order, i)
+					return i * 10, nil
+				})
+			}
+		}
+
+		results, err := Series(ctx, step(1), step(2), step(3)).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []int{10, 20, 30}, results)
+		require.Equal(t, []int{1, 2, 3}, order)
+	})
+
+	t.Run("StopsOnError", func(t *testing.T) {
+		ctx := context.Background()
+		called := false
+		_, err := Series(ctx,
+			func(ctx context.Context) *Promise[int] {
+				return AsyncTask(func() (int, error) { return 0, errors.New("boom") })
+			},
+			func(ctx context.Context) *Promise[int] {
+				called = true
+				return AsyncTask(func() (int, error) { return 1, nil })
+			},
+		).Await(ctx)
+		require.EqualError(t, err, "boom")
+		require.False(t, called)
+	})
+}
+
+func TestWaterfall(t *testing.T) {
+	ctx := context.Background()
+	double := func(ctx context.Context, v int) *Promise[int] {
+		return AsyncTask(func() (int, error) { return v * 2, nil })
+	}
+
+	result, err := Waterfall(ctx, 1, double, double, double).Await(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 8, result)
+}
+
+func TestReduceAsync(t *testing.T) {
+	t.Run("Sum", func(t *testing.T) {
+		ctx := context.Background()
+		result, err := ReduceAsync(ctx, []int{1, 2, 3}, "", func(acc string, v int) *Promise[string] {
+			return AsyncTask(func() (string, error) { return acc + string(rune('0'+v)), nil })
+		}).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "123", result)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := ReduceAsync(ctx, []int{1}, 0, func(acc, v int) *Promise[int] {
+			return AsyncTask(func() (int, error) { return acc + v, nil })
+		}).Await(context.Background())
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
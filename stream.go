@@ -0,0 +1,229 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrStreamClosed is returned by emit once the stream has ended or its consumer has closed it
+var ErrStreamClosed = errors.New("stream closed")
+
+// errStreamDone is returned by an operator step to end the derived stream without an error
+var errStreamDone = errors.New("stream done")
+
+// Stream represents an asynchronous producer of any number of values of type T.
+// Values are handed over through a bounded buffer, so a producer that gets ahead of its consumer waits in emit.
+type Stream[T any] struct {
+	ch        chan T
+	closed    chan struct{}
+	closeOnce sync.Once
+	endOnce   sync.Once
+	ended     atomic.Bool
+	err       error
+}
+
+// NewStream creates a new Stream fed by the given producer with a buffer of the given size.
+// The producer calls emit for every value, then done or fail to end the stream; returning also ends it.
+// emit waits while the buffer is full and returns ErrStreamClosed once the consumer has closed the stream.
+// The callbacks must not be called concurrently.
+func NewStream[T any](producer func(emit func(T) error, fail func(error), done func()), buffer int) *Stream[T] {
+	return NewStreamWithPool(producer, buffer, defaultPool)
+}
+
+// NewStreamWithPool creates a new Stream fed by the given producer running on the given pool
+func NewStreamWithPool[T any](producer func(emit func(T) error, fail func(error), done func()), buffer int, pool Pool) *Stream[T] {
+	if producer == nil {
+		panic("producer must not be nil")
+	}
+	if pool == nil {
+		panic("pool must not be nil")
+	}
+	s := newStream[T](buffer)
+	pool.Go(func() {
+		defer s.end(nil)
+		defer s.handlePanic()
+		producer(s.emit, s.end, func() { s.end(nil) })
+	})
+	return s
+}
+
+func newStream[T any](buffer int) *Stream[T] {
+	if buffer < 0 {
+		buffer = 0
+	}
+	return &Stream[T]{
+		ch:     make(chan T, buffer),
+		closed: make(chan struct{}),
+	}
+}
+
+// Next waits for the next value of the Stream.
+// It returns io.EOF once the stream is done, or the error the producer failed with.
+func (s *Stream[T]) Next(ctx context.Context) (T, error) {
+	var t T
+	select {
+	case <-ctx.Done():
+		return t, ctx.Err()
+	case value, ok := <-s.ch:
+		if !ok {
+			if s.err != nil {
+				return t, s.err
+			}
+			return t, io.EOF
+		}
+		return value, nil
+	}
+}
+
+// Close tells the producer that no more values will be consumed
+func (s *Stream[T]) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+	})
+}
+
+func (s *Stream[T]) emit(value T) error {
+	if s.ended.Load() {
+		return ErrStreamClosed
+	}
+	select {
+	case <-s.closed:
+		return ErrStreamClosed
+	default:
+	}
+
+	select {
+	case <-s.closed:
+		return ErrStreamClosed
+	case s.ch <- value:
+		return nil
+	}
+}
+
+func (s *Stream[T]) end(err error) {
+	s.endOnce.Do(func() {
+		s.err = err
+		s.ended.Store(true)
+		close(s.ch)
+	})
+}
+
+func (s *Stream[T]) handlePanic() {
+	if r := recover(); r != nil {
+		s.end(panicToError(r))
+	}
+}
+
+// pipe creates a Stream fed from src through step, which is called for every value of src.
+// Closing the returned stream closes src.
+func pipe[A, B any](src *Stream[A], buffer int, step func(value A, emit func(B) error) error) *Stream[B] {
+	dst := newStream[B](buffer)
+	defaultPool.Go(func() {
+		defer src.Close()
+		defer dst.end(nil)
+		defer dst.handlePanic()
+		for {
+			select {
+			case <-dst.closed:
+				return
+			case value, ok := <-src.ch:
+				if !ok {
+					dst.end(src.err)
+					return
+				}
+				if err := step(value, dst.emit); err != nil {
+					if !errors.Is(err, errStreamDone) && !errors.Is(err, ErrStreamClosed) {
+						dst.end(err)
+					}
+					return
+				}
+			}
+		}
+	})
+	return dst
+}
+
+// Map returns a Stream of the values of s transformed by fn.
+// The returned stream fails with the first error returned by fn.
+func Map[A, B any](s *Stream[A], fn func(A) (B, error)) *Stream[B] {
+	return pipe(s, cap(s.ch), func(value A, emit func(B) error) error {
+		result, err := fn(value)
+		if err != nil {
+			return err
+		}
+		return emit(result)
+	})
+}
+
+// Filter returns a Stream of the values of s for which fn returns true
+func Filter[T any](s *Stream[T], fn func(T) bool) *Stream[T] {
+	return pipe(s, cap(s.ch), func(value T, emit func(T) error) error {
+		if !fn(value) {
+			return nil
+		}
+		return emit(value)
+	})
+}
+
+// Buffer returns a Stream of the values of s with a buffer of the given size
+func Buffer[T any](s *Stream[T], size int) *Stream[T] {
+	return pipe(s, size, func(value T, emit func(T) error) error {
+		return emit(value)
+	})
+}
+
+// Take returns a Stream of the first n values of s. s is closed once n values have been taken.
+func Take[T any](s *Stream[T], n int) *Stream[T] {
+	if n <= 0 {
+		s.Close()
+		empty := newStream[T](0)
+		empty.end(nil)
+		return empty
+	}
+
+	taken := 0
+	return pipe(s, cap(s.ch), func(value T, emit func(T) error) error {
+		if err := emit(value); err != nil {
+			return err
+		}
+		taken++
+		if taken == n {
+			return errStreamDone
+		}
+		return nil
+	})
+}
+
+// Reduce returns a Promise that folds the values of s into a single value using fn.
+// It rejects with the first error of the stream or of fn, and closes s when it stops early.
+func Reduce[T, A any](ctx context.Context, s *Stream[T], init A, fn func(A, T) (A, error)) *Promise[A] {
+	return New(func(resolve func(A), reject func(error)) {
+		defer s.Close()
+		acc := init
+		for {
+			value, err := s.Next(ctx)
+			if errors.Is(err, io.EOF) {
+				resolve(acc)
+				return
+			}
+			if err != nil {
+				reject(err)
+				return
+			}
+			if acc, err = fn(acc, value); err != nil {
+				reject(err)
+				return
+			}
+		}
+	})
+}
+
+// Collect returns a Promise that resolves with all values of s
+func Collect[T any](ctx context.Context, s *Stream[T]) *Promise[[]T] {
+	return Reduce(ctx, s, []T(nil), func(values []T, value T) ([]T, error) {
+		return append(values, value), nil
+	})
+}
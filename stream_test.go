@@ -0,0 +1,104 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func countTo(n int) *Stream[int] {
+	return NewStream(func(emit func(int) error, fail func(error), done func()) {
+		for i := 1; i <= n; i++ {
+			if err := emit(i); err != nil {
+				return
+			}
+		}
+		done()
+	}, 2)
+}
+
+func TestStream(t *testing.T) {
+	t.Run("Next", func(t *testing.T) {
+		ctx := context.Background()
+		s := countTo(2)
+		for _, want := range []int{1, 2} {
+			value, err := s.Next(ctx)
+			require.NoError(t, err)
+			require.Equal(t, want, value)
+		}
+		_, err := s.Next(ctx)
+		require.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		ctx := context.Background()
+		s := NewStream(func(emit func(int) error, fail func(error), done func()) {
+			_ = emit(1)
+			fail(errors.New("boom"))
+		}, 1)
+		_, err := Collect(ctx, s).Await(ctx)
+		require.EqualError(t, err, "boom")
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		ctx := context.Background()
+		s := NewStream(func(emit func(int) error, fail func(error), done func()) {
+			panic("boom")
+		}, 0)
+		_, err := s.Next(ctx)
+		require.EqualError(t, err, "boom")
+	})
+
+	t.Run("CloseStopsProducer", func(t *testing.T) {
+		ctx := context.Background()
+		stopped := make(chan error, 1)
+		s := NewStream(func(emit func(int) error, fail func(error), done func()) {
+			for i := 0; ; i++ {
+				if err := emit(i); err != nil {
+					stopped <- err
+					return
+				}
+			}
+		}, 0)
+		_, err := s.Next(ctx)
+		require.NoError(t, err)
+		s.Close()
+		require.ErrorIs(t, <-stopped, ErrStreamClosed)
+	})
+}
+
+func TestStream_Operators(t *testing.T) {
+	t.Run("MapFilterTake", func(t *testing.T) {
+		ctx := context.Background()
+		even := Filter(countTo(100), func(v int) bool { return v%2 == 0 })
+		labels := Map(even, func(v int) (string, error) { return strconv.Itoa(v), nil })
+		result, err := Collect(ctx, Take(Buffer(labels, 4), 3)).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"2", "4", "6"}, result)
+	})
+
+	t.Run("MapError", func(t *testing.T) {
+		ctx := context.Background()
+		s := Map(countTo(3), func(v int) (int, error) {
+			if v == 2 {
+				return 0, errors.New("boom")
+			}
+			return v, nil
+		})
+		_, err := Collect(ctx, s).Await(ctx)
+		require.EqualError(t, err, "boom")
+	})
+
+	t.Run("Reduce", func(t *testing.T) {
+		ctx := context.Background()
+		sum, err := Reduce(ctx, countTo(4), 0, func(acc, v int) (int, error) {
+			return acc + v, nil
+		}).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 10, sum)
+	})
+}
@@ -21,6 +21,58 @@ var (
 		Name: "concurrent_promises",
 		Help: "The number of promises currently executing",
 	})
+
+	circuitBreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "circuit_breaker_state",
+		Help: "The current state of circuit breakers (0 closed, 1 open, 2 half-open)",
+	}, []string{"name"})
+
+	circuitBreakerTransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "circuit_breaker_transitions_total",
+		Help: "The total number of circuit breaker state transitions",
+	}, []string{"name", "from", "to"})
+
+	rateLimitWaitTime = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "promise_rate_limit_wait_seconds",
+		Help:    "The time tasks spend waiting for a rate limiter in seconds, by rate-limited pool (empty for RateLimit)",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 14), // From 1ms to ~8s
+	}, []string{"name"})
+
+	hedgesLaunched = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "promise_hedges_total",
+		Help: "The total number of hedged attempts launched after the first one",
+	})
+
+	weightedPoolUsed = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "weighted_pool_used_weight",
+		Help: "The total weight of the tasks currently admitted by weighted pools",
+	}, []string{"name"})
+
+	weightedPoolWaitTime = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "weighted_pool_wait_seconds",
+		Help:    "The time tasks spend waiting to be admitted by weighted pools in seconds",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 14), // From 1ms to ~8s
+	}, []string{"name"})
+
+	adaptivePoolLimit = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "adaptive_pool_limit",
+		Help: "The current concurrency limit of adaptive pools",
+	}, []string{"name"})
+
+	bulkheadActive = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "bulkhead_active",
+		Help: "The number of tasks currently running in bulkheads",
+	}, []string{"name"})
+
+	bulkheadQueued = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "bulkhead_queued",
+		Help: "The number of tasks currently waiting for a slot in bulkheads",
+	}, []string{"name"})
+
+	bulkheadRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "bulkhead_rejected_total",
+		Help: "The total number of tasks rejected by full bulkheads",
+	}, []string{"name"})
 )
 
 func incrementPromisesCreated() {
@@ -38,3 +90,44 @@ func incrementConcurrentPromises() {
 func decrementConcurrentPromises() {
 	concurrentPromises.Dec()
 }
+
+func setBreakerState(name string, state BreakerState) {
+	circuitBreakerState.WithLabelValues(name).Set(float64(state))
+}
+
+func observeBreakerStateChange(name string, from, to BreakerState) {
+	circuitBreakerTransitions.WithLabelValues(name, from.String(), to.String()).Inc()
+	setBreakerState(name, to)
+}
+
+func observeRateLimitWait(name string, seconds float64) {
+	rateLimitWaitTime.WithLabelValues(name).Observe(seconds)
+}
+
+func incrementHedges() {
+	hedgesLaunched.Inc()
+}
+
+func setWeightedPoolUsed(name string, used int64) {
+	weightedPoolUsed.WithLabelValues(name).Set(float64(used))
+}
+
+func observeWeightedPoolWait(name string, seconds float64) {
+	weightedPoolWaitTime.WithLabelValues(name).Observe(seconds)
+}
+
+func setAdaptivePoolLimit(name string, limit int) {
+	adaptivePoolLimit.WithLabelValues(name).Set(float64(limit))
+}
+
+func setBulkheadActive(name string, active int) {
+	bulkheadActive.WithLabelValues(name).Set(float64(active))
+}
+
+func setBulkheadQueued(name string, queued int) {
+	bulkheadQueued.WithLabelValues(name).Set(float64(queued))
+}
+
+func incrementBulkheadRejected(name string) {
+	bulkheadRejected.WithLabelValues(name).Inc()
+}
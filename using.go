@@ -0,0 +1,81 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+)
+
+// Using acquires a resource, passes it to use and releases it whatever the outcome.
+// release receives the resource and the error use failed with, if any. It is called after use succeeds,
+// rejects or panics. If ctx is done first, the returned Promise rejects right away and the resource is
+// released once use settles, or once acquire resolves if the resource was still being acquired.
+// An error returned by release is joined with the error of use; release errors that happen after ctx
+// is done have no Promise left to report them and are dropped.
+func Using[R, T any](ctx context.Context, acquire func(ctx context.Context) *Promise[R], use func(R) *Promise[T], release func(R, error) error) *Promise[T] {
+	if acquire == nil || use == nil || release == nil {
+		panic("acquire, use and release must not be nil")
+	}
+
+	return New(func(resolve func(T), reject func(error)) {
+		acquiring := acquire(ctx)
+		resource, err := acquiring.Await(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				// The resource may still be acquired after we stopped waiting for it
+				acquiring.OnResolve(func(resource R) {
+					_ = callRelease(release, resource, ctx.Err())
+				})
+			}
+			reject(err)
+			return
+		}
+
+		using, err := callUse(use, resource)
+		if err != nil {
+			reject(errors.Join(err, callRelease(release, resource, err)))
+			return
+		}
+
+		result, err := using.Await(ctx)
+		if err != nil && ctx.Err() != nil && !using.settled() {
+			// use is still running, release the resource once it is done with it
+			using.OnSettle(func(_ T, useErr error) {
+				_ = callRelease(release, resource, errors.Join(ctx.Err(), useErr))
+			})
+			reject(err)
+			return
+		}
+
+		if releaseErr := callRelease(release, resource, err); releaseErr != nil {
+			reject(errors.Join(err, releaseErr))
+		} else if err != nil {
+			reject(err)
+		} else {
+			resolve(result)
+		}
+	})
+}
+
+// callUse calls use, turning a panic into an error so that the resource is still released
+func callUse[R, T any](use func(R) *Promise[T], resource R) (p *Promise[T], err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicToError(r)
+		}
+	}()
+	p = use(resource)
+	if p == nil {
+		return nil, errors.New("use returned a nil promise")
+	}
+	return p, nil
+}
+
+// callRelease calls release, turning a panic into an error
+func callRelease[R any](release func(R, error) error, resource R, useErr error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicToError(r)
+		}
+	}()
+	return release(resource, useErr)
+}
@@ -0,0 +1,92 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testResource struct {
+	released chan error
+}
+
+func acquireTestResource(ctx context.Context) *Promise[*testResource] {
+	return AsyncTask(func() (*testResource, error) {
+		return &testResource{released: make(chan error, 1)}, nil
+	})
+}
+
+func TestUsing(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ctx := context.Background()
+		var res *testResource
+		result, err := Using(ctx, acquireTestResource, func(r *testResource) *Promise[string] {
+			res = r
+			return AsyncTask(func() (string, error) { return "used", nil })
+		}, func(r *testResource, err error) error {
+			r.released <- err
+			return nil
+		}).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "used", result)
+		require.NoError(t, <-res.released)
+	})
+
+	t.Run("RejectAndReleaseError", func(t *testing.T) {
+		ctx := context.Background()
+		useErr := errors.New("query failed")
+		releaseErr := errors.New("close failed")
+		var res *testResource
+		_, err := Using(ctx, acquireTestResource, func(r *testResource) *Promise[string] {
+			res = r
+			return AsyncTask(func() (string, error) { return "", useErr })
+		}, func(r *testResource, err error) error {
+			r.released <- err
+			return releaseErr
+		}).Await(ctx)
+		require.ErrorIs(t, err, useErr)
+		require.ErrorIs(t, err, releaseErr)
+		require.ErrorIs(t, <-res.released, useErr)
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		ctx := context.Background()
+		var res *testResource
+		_, err := Using(ctx, acquireTestResource, func(r *testResource) *Promise[string] {
+			res = r
+			panic("boom")
+		}, func(r *testResource, err error) error {
+			r.released <- err
+			return nil
+		}).Await(ctx)
+		require.EqualError(t, err, "boom")
+		require.EqualError(t, <-res.released, "boom")
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		resources := make(chan *testResource, 1)
+		finish := make(chan struct{})
+		p := Using(ctx, acquireTestResource, func(r *testResource) *Promise[string] {
+			resources <- r
+			return New(func(resolve func(string), reject func(error)) {
+				<-finish
+				resolve("late")
+			})
+		}, func(r *testResource, err error) error {
+			r.released <- err
+			return nil
+		})
+
+		res := <-resources
+		cancel()
+		_, err := p.Await(context.Background())
+		require.ErrorIs(t, err, context.Canceled)
+		require.Empty(t, res.released, "released while still in use")
+
+		close(finish)
+		require.ErrorIs(t, <-res.released, context.Canceled)
+	})
+}
@@ -0,0 +1,68 @@
+package promise4g
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrWeightExceedsCapacity is returned for tasks heavier than the capacity of their WeightedPool
+var ErrWeightExceedsCapacity = errors.New("task weight exceeds pool capacity")
+
+// WeightedPool is a Pool that admits tasks while their total weight stays within its capacity.
+// Tasks declare their weight with WithWeight and bound their wait with WithContext.
+// Waiting tasks are admitted in order, so a heavy task is not starved by lighter ones.
+type WeightedPool struct {
+	name  string
+	pool  Pool
+	queue *admissionQueue
+}
+
+// NewWeightedPool creates a WeightedPool with the given capacity that runs admitted tasks on pool.
+// The name identifies the pool in metrics.
+func NewWeightedPool(name string, pool Pool, capacity int64) *WeightedPool {
+	if pool == nil {
+		panic("pool must not be nil")
+	}
+	if capacity <= 0 {
+		panic("capacity must be positive")
+	}
+	return &WeightedPool{
+		name: name,
+		pool: pool,
+		queue: &admissionQueue{
+			capacity:   capacity,
+			maxWaiting: -1,
+			observe: func(used int64, _ int) {
+				setWeightedPoolUsed(name, used)
+			},
+			observeWait: func(d time.Duration) {
+				observeWeightedPoolWait(name, d.Seconds())
+			},
+		},
+	}
+}
+
+// Go runs f with a weight of 1 once the pool admits it
+func (wp *WeightedPool) Go(f func()) {
+	goAdmitted(wp, wp.admit, f)
+}
+
+func (wp *WeightedPool) String() string {
+	return fmt.Sprintf("weighted %s", wp.name)
+}
+
+func (wp *WeightedPool) admit(ctx context.Context, weight int64, run func(), fail func(error)) {
+	if weight < 0 {
+		weight = 0
+	}
+	if weight > wp.queue.capacity {
+		fail(fmt.Errorf("%w: %d > %d", ErrWeightExceedsCapacity, weight, wp.queue.capacity))
+		return
+	}
+
+	wp.queue.acquire(ctx, weight, func() {
+		wp.queue.start(wp.pool, weight, run, fail)
+	}, fail)
+}
@@ -0,0 +1,77 @@
+package promise4g
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeightedPool(t *testing.T) {
+	t.Run("LimitsTotalWeight", func(t *testing.T) {
+		ctx := context.Background()
+		pool := NewWeightedPool("limits", newDefaultPool(), 10)
+		var tracker peakTracker
+		task := func(weight int64) *Promise[int64] {
+			return NewWithOptions(func(resolve func(int64), reject func(error)) {
+				done := tracker.trackPeak(weight)
+				time.Sleep(20 * time.Millisecond)
+				done()
+				resolve(weight)
+			}, WithPool(pool), WithWeight(weight))
+		}
+
+		results, err := All(ctx, task(6), task(6), task(4), task(4)).Await(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []int64{6, 6, 4, 4}, results)
+		require.LessOrEqual(t, tracker.max(), int64(10))
+	})
+
+	t.Run("TooHeavy", func(t *testing.T) {
+		pool := NewWeightedPool("heavy", newDefaultPool(), 10)
+		_, err := NewWithOptions(func(resolve func(int), reject func(error)) {
+			resolve(1)
+		}, WithPool(pool), WithWeight(11)).Await(context.Background())
+		require.ErrorIs(t, err, ErrWeightExceedsCapacity)
+	})
+
+	t.Run("WaitCanceled", func(t *testing.T) {
+		pool := NewWeightedPool("canceled", newDefaultPool(), 1)
+		release := make(chan struct{})
+		defer close(release)
+		NewWithOptions(func(resolve func(int), reject func(error)) {
+			<-release
+			resolve(1)
+		}, WithPool(pool))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		ran := false
+		_, err := NewWithOptions(func(resolve func(int), reject func(error)) {
+			ran = true
+			resolve(2)
+		}, WithPool(pool), WithContext(ctx)).Await(context.Background())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.False(t, ran)
+	})
+
+	t.Run("PoolRefuses", func(t *testing.T) {
+		ctx := context.Background()
+		inner := NewManagedPool(nil)
+		require.NoError(t, inner.Shutdown(ctx))
+		pool := NewWeightedPool("refuses", inner, 10)
+
+		_, err := NewWithOptions(func(resolve func(int), reject func(error)) {
+			resolve(1)
+		}, WithPool(pool), WithWeight(4)).Await(ctx)
+		require.ErrorIs(t, err, ErrPoolClosed)
+		require.PanicsWithError(t, ErrPoolClosed.Error(), func() {
+			pool.Go(func() {})
+		})
+
+		pool.queue.mu.Lock()
+		defer pool.queue.mu.Unlock()
+		require.Zero(t, pool.queue.used)
+	})
+}